@@ -6,7 +6,6 @@ import (
 	_ "image/gif" // needed by image
 	_ "image/jpeg"
 	_ "image/png"
-	"math"
 	"os"
 
 	"gioui.org/f32"
@@ -22,12 +21,18 @@ import (
 
 // textops places text
 func (c *Canvas) textops(x, y, size float32, alignment text.Alignment, s string, fillcolor color.NRGBA) {
+	c.textdir(x, y, size, alignment, s, fillcolor, c.rtl(s))
+}
+
+// textdir places text in a direction: right to left text starts at x and extends to the left
+func (c *Canvas) textdir(x, y, size float32, alignment text.Alignment, s string, fillcolor color.NRGBA, rtl bool) {
+	defer c.useDirection(rtl)()
 	offset := x
-	switch alignment {
-	case text.End:
-		offset = x - c.Width
-	case text.Middle:
+	switch {
+	case alignment == text.Middle:
 		offset = x - c.Width/2
+	case (alignment == text.End) != rtl:
+		offset = x - c.Width
 	}
 	stack := op.Offset(image.Point{X: int(offset), Y: int(y - size)}).Push(c.Context.Ops) // shift to use baseline
 	l := material.Label(c.Theme, unit.Sp(size), s)
@@ -42,9 +47,11 @@ func (c *Canvas) AbsTextWrap(x, y, size, width float32, s string, fillcolor colo
 	stack := op.Offset(image.Point{X: int(x), Y: int(y - size)}).Push(c.Context.Ops) // shift to use baseline
 	l := material.Label(c.Theme, unit.Sp(size), s)
 	l.Color = fillcolor
-	c.Context.Constraints.Max.X = int(width)
+	defer c.useDirection(c.rtl(s))()
+	// the lines are aligned within the minimum width: right to left lines end at the right of the column
+	c.Context.Constraints.Min.X, c.Context.Constraints.Max.X = int(width), int(width)
 	l.Layout(c.Context)
-	c.Context.Constraints.Max.X = int(c.Width) // restore width...
+	c.Context.Constraints.Min.X, c.Context.Constraints.Max.X = int(c.Width), int(c.Width) // restore width...
 	stack.Pop()
 }
 
@@ -150,10 +157,17 @@ func (c *Canvas) AbsPolygon(x, y []float32, fillcolor color.NRGBA) {
 	if len(x) != len(y) {
 		return
 	}
-	path := new(clip.Path)
 	ops := c.Context.Ops
+	stack := clip.Outline{Path: c.polygonPath(x, y)}.Op().Push(ops)
+	paint.ColorOp{Color: fillcolor}.Add(ops)
+	paint.PaintOp{}.Add(ops)
+	stack.Pop()
+}
 
-	path.Begin(ops)
+// polygonPath makes the path of a polygon with vertices in x and y
+func (c *Canvas) polygonPath(x, y []float32) clip.PathSpec {
+	path := new(clip.Path)
+	path.Begin(c.Context.Ops)
 	path.Move(f32.Point{X: x[0], Y: y[0]})
 
 	l := len(x)
@@ -164,11 +178,7 @@ func (c *Canvas) AbsPolygon(x, y []float32, fillcolor color.NRGBA) {
 	path.Line(f32.Point{X: x[0] - x[l-1], Y: y[0] - y[l-1]})
 	path.Line(point)
 	path.Close()
-	stack := clip.Outline{Path: path.End()}.Op().Push(ops)
-	paint.ColorOp{Color: fillcolor}.Add(ops)
-	paint.PaintOp{}.Add(ops)
-	stack.Pop()
-
+	return path.End()
 }
 
 // AbsLine makes a line from (x0,y0) to (x1, y1) using absolute coordinates
@@ -260,23 +270,21 @@ func (c *Canvas) AbsStrokedCubicBezier(x, y, cx1, cy1, cx2, cy2, ex, ey, size fl
 
 // AbsCircle makes a circle centered at (x, y), radius r
 func (c *Canvas) AbsCircle(x, y, radius float32, fillcolor color.NRGBA) {
-	path := new(clip.Path)
 	ops := c.Context.Ops
-	const k = 0.551915024494 // http://spencermortensen.com/articles/bezier-circle/
-
-	path.Begin(ops)
-	path.Move(f32.Point{X: x + radius, Y: y})
-	path.Cube(f32.Point{X: 0, Y: radius * k}, f32.Point{X: -radius + radius*k, Y: radius}, f32.Point{X: -radius, Y: radius})    // SE
-	path.Cube(f32.Point{X: -radius * k, Y: 0}, f32.Point{X: -radius, Y: -radius + radius*k}, f32.Point{X: -radius, Y: -radius}) // SW
-	path.Cube(f32.Point{X: 0, Y: -radius * k}, f32.Point{X: radius - radius*k, Y: -radius}, f32.Point{X: radius, Y: -radius})   // NW
-	path.Cube(f32.Point{X: radius * k, Y: 0}, f32.Point{X: radius, Y: radius - radius*k}, f32.Point{X: radius, Y: radius})      // NE
-	path.Close()
-	stack := clip.Outline{Path: path.End()}.Op().Push(ops)
+	stack := clip.Outline{Path: c.circlePath(x, y, radius)}.Op().Push(ops)
 	paint.ColorOp{Color: fillcolor}.Add(ops)
 	paint.PaintOp{}.Add(ops)
 	stack.Pop()
 }
 
+// circlePath makes the path of a circle centered at (x, y), with the specified radius
+func (c *Canvas) circlePath(x, y, radius float32) clip.PathSpec {
+	path := new(clip.Path)
+	path.Begin(c.Context.Ops)
+	addCircle(path, x, y, radius)
+	return path.End()
+}
+
 // AbsEllipse makes a ellipse centered at (x, y) radii (w, h)
 func (c *Canvas) AbsEllipse(x, y, w, h float32, fillcolor color.NRGBA) {
 	path := new(clip.Path)
@@ -299,82 +307,53 @@ func (c *Canvas) AbsEllipse(x, y, w, h float32, fillcolor color.NRGBA) {
 // N.B: derived from the clipLoader function in widget/material/loader.go
 func (c *Canvas) AbsArc(x, y, radius float32, start, end float64, fillcolor color.NRGBA) {
 	ops := c.Context.Ops
-	sine, cose := math.Sincos(start)
-	path := new(clip.Path)
-	path.Begin(ops)
-	path.Move(f32.Pt(x, y))                                 // move to the center
-	pen := f32.Pt(float32(cose), float32(sine)).Mul(radius) // starting point
-	path.Line(pen)
-
-	// The clip path uses quadratic beziér curves to approximate
-	// a circle arc. Minimize the error by capping the length of
-	// each curve segment.
-	const maxArcLen = 20.0
-	arcPerRadian := float64(radius) * math.Pi
-	anglePerSegment := maxArcLen / arcPerRadian
-	for angle := start; angle < end; {
-		angle += anglePerSegment
-		if angle > end {
-			angle = end
-		}
-		sins, coss := sine, cose
-		sine, cose = math.Sincos(angle)
-
-		// https://pomax.github.io/bezierinfo/#circles
-		div := 1.0 / (coss*sine - cose*sins)
-		ctrlPt := f32.Point{X: float32((sine - sins) * div), Y: -float32((cose - coss) * div)}.Mul(radius)
-		endPt := f32.Pt(float32(cose), float32(sine)).Mul(radius)
-		path.Quad(ctrlPt.Sub(pen), endPt.Sub(pen))
-		pen = endPt
-	}
-	path.Close()
-	stack := clip.Outline{Path: path.End()}.Op().Push(ops)
+	stack := clip.Outline{Path: c.arcPath(x, y, radius, start, end)}.Op().Push(ops)
 	paint.ColorOp{Color: fillcolor}.Add(ops)
 	paint.PaintOp{}.Add(ops)
 	stack.Pop()
 }
 
+// arcPath makes the path of a circular arc centered at (x, y), through angles start and end
+func (c *Canvas) arcPath(x, y, radius float32, start, end float64) clip.PathSpec {
+	path := new(clip.Path)
+	path.Begin(c.Context.Ops)
+	path.MoveTo(f32.Pt(x, y)) // move to the center
+	path.LineTo(arcPoint(x, y, radius, radius, start))
+	if end > start {
+		arcTo(path, x, y, radius, radius, start, end)
+	}
+	path.Close()
+	return path.End()
+}
+
 // AbsTranslate moves current location by (x,y)
-func (c *Canvas) AbsTranslate(x, y float32) op.TransformStack {
-	ops := c.Context.Ops
-	//op.InvalidateOp{}.Add(ops)
-	c.Context.Execute(op.InvalidateCmd{})
-	stack := op.Offset(image.Pt(0, 0)).Push(ops)
-	tr := f32.Affine2D{}
-	tr = tr.Offset(f32.Pt(x, y))
-	op.Affine(tr).Add(ops)
-	return stack
+func (c *Canvas) AbsTranslate(x, y float32) TransformStack {
+	return c.pushAffine(f32.Affine2D{}.Offset(f32.Pt(x, y)))
 }
 
 // AbsRotate rotates around (x,y) using angle (radians)
-func (c *Canvas) AbsRotate(x, y, angle float32) op.TransformStack {
-	ops := c.Context.Ops
-	//op.InvalidateOp{}.Add(ops)
-	c.Context.Execute(op.InvalidateCmd{})
-	stack := op.Offset(image.Pt(0, 0)).Push(ops)
-	tr := f32.Affine2D{}.Rotate(f32.Pt(x, y), angle)
-	op.Affine(tr).Add(ops)
-	return stack
+func (c *Canvas) AbsRotate(x, y, angle float32) TransformStack {
+	return c.pushAffine(f32.Affine2D{}.Rotate(f32.Pt(x, y), angle))
 }
 
 // AbsScale scales by factor at (x,y)
-func (c *Canvas) AbsScale(x, y, factor float32) op.TransformStack {
-	ops := c.Context.Ops
-	//op.InvalidateOp{}.Add(ops)
-	c.Context.Execute(op.InvalidateCmd{})
-	stack := op.Offset(image.Pt(0, 0)).Push(ops)
-	tr := f32.Affine2D{}.Scale(f32.Pt(x, y), f32.Pt(factor, factor))
-	op.Affine(tr).Add(ops)
-	return stack
+func (c *Canvas) AbsScale(x, y, factor float32) TransformStack {
+	return c.pushAffine(f32.Affine2D{}.Scale(f32.Pt(x, y), f32.Pt(factor, factor)))
 }
 
 // AbsShear shears at (x,y) using angle ax and ay
-func (c *Canvas) AbsShear(x, y, ax, ay float32) op.TransformStack {
+func (c *Canvas) AbsShear(x, y, ax, ay float32) TransformStack {
+	return c.pushAffine(f32.Affine2D{}.Shear(f32.Pt(x, y), ax, ay))
+}
+
+// pushAffine begins a transformation, recording it for Pop and CurrentTransform
+func (c *Canvas) pushAffine(tr f32.Affine2D) TransformStack {
 	ops := c.Context.Ops
 	//op.InvalidateOp{}.Add(ops)
 	c.Context.Execute(op.InvalidateCmd{})
 	stack := op.Offset(image.Pt(0, 0)).Push(ops)
-	tr := f32.Affine2D{}.Shear(f32.Pt(x, y), ax, ay)
 	op.Affine(tr).Add(ops)
-	return stack
+	c.keep(stack)
+	c.track(stack, tr)
+	return TransformStack{stack, c}
 }
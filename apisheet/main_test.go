@@ -0,0 +1,28 @@
+package main
+
+import (
+	"testing"
+
+	"gioui.org/app"
+	"github.com/ajstarks/giocanvas"
+	"github.com/ajstarks/giocanvas/golden"
+)
+
+func TestAPISheet(t *testing.T) {
+	const width, height = 1600, 1000
+	logoimg, err := getimage("logo.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		name     string
+		showgrid bool
+	}{
+		{"apisheet", false},
+		{"apisheet-grid", true},
+	} {
+		canvas := giocanvas.NewCanvas(width, height, app.FrameEvent{})
+		apisheet(canvas, logoimg, test.showgrid)
+		golden.Check(t, test.name, width, height, canvas.Context.Ops)
+	}
+}
@@ -0,0 +1,118 @@
+package giocanvas
+
+import (
+	"image/color"
+	"math"
+
+	"gioui.org/f32"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+)
+
+// Stroked arcs and annular sectors. As with AbsArc, the angles of the Abs methods are in radians,
+// and increase clockwise on the screen; the angles of ArcLine and EllipticalArcLine increase counter-clockwise.
+
+// arcPoint returns the point at angle a on the ellipse centered at (x, y) with radii (rx, ry)
+func arcPoint(x, y, rx, ry float32, a float64) f32.Point {
+	sin, cos := math.Sincos(a)
+	return f32.Pt(x+float32(cos)*rx, y+float32(sin)*ry)
+}
+
+// arcTo adds an elliptical arc centered at (x, y), with radii (rx, ry), from angle start to end,
+// to a path whose pen is at the beginning of the arc. The arc is approximated with quadratic
+// beziér curves; the error is minimized by capping the length of each curve segment.
+// N.B: derived from the clipLoader function in widget/material/loader.go
+func arcTo(path *clip.Path, x, y, rx, ry float32, start, end float64) {
+	const maxArcLen = 20.0
+	r := math.Max(math.Abs(float64(rx)), math.Abs(float64(ry)))
+	sweep := math.Abs(end - start)
+	n := int(math.Ceil(math.Max(sweep*r*math.Pi/maxArcLen, sweep/(math.Pi/2))))
+	if n == 0 {
+		return
+	}
+	sins, coss := math.Sincos(start)
+	for i := 1; i <= n; i++ {
+		sine, cose := math.Sincos(start + (end-start)*float64(i)/float64(n))
+		// https://pomax.github.io/bezierinfo/#circles
+		div := 1.0 / (coss*sine - cose*sins)
+		ctrl := f32.Pt(x+float32((sine-sins)*div)*rx, y-float32((cose-coss)*div)*ry)
+		path.QuadTo(ctrl, f32.Pt(x+float32(cose)*rx, y+float32(sine)*ry))
+		sins, coss = sine, cose
+	}
+}
+
+// arcSweep returns the end of an arc beginning at a1, so that it turns toward a2 by less than
+// a full circle; if a2 is a whole number of turns from a1, the arc is a full circle.
+func arcSweep(a1, a2 float64) float64 {
+	const twoPi = math.Pi * 2
+	d := math.Mod(a2-a1, twoPi)
+	if d < 0 {
+		d += twoPi
+	}
+	if d == 0 && a2 != a1 {
+		d = twoPi
+	}
+	return a1 + d
+}
+
+// AbsArcLine makes a stroked circular arc centered at (x, y), through angles start and end
+func (c *Canvas) AbsArcLine(x, y, radius float32, start, end float64, size float32, strokecolor color.NRGBA) {
+	c.AbsEllipticalArcLine(x, y, radius, radius, start, end, size, strokecolor)
+}
+
+// AbsEllipticalArcLine makes a stroked elliptical arc centered at (x, y), with radii (rx, ry), through angles start and end
+func (c *Canvas) AbsEllipticalArcLine(x, y, rx, ry float32, start, end float64, size float32, strokecolor color.NRGBA) {
+	path := new(clip.Path)
+	path.Begin(c.Context.Ops)
+	path.MoveTo(arcPoint(x, y, rx, ry, start))
+	arcTo(path, x, y, rx, ry, start, end)
+	paint.FillShape(c.Context.Ops, strokecolor, clip.Stroke{Path: path.End(), Width: size}.Op())
+}
+
+// annulusPath makes the path of an annular sector centered at (x, y), between radii inner and outer,
+// through angles start and end
+func (c *Canvas) annulusPath(x, y, inner, outer float32, start, end float64) clip.PathSpec {
+	path := new(clip.Path)
+	path.Begin(c.Context.Ops)
+	path.MoveTo(arcPoint(x, y, outer, outer, start))
+	arcTo(path, x, y, outer, outer, start, end)
+	path.LineTo(arcPoint(x, y, inner, inner, end))
+	arcTo(path, x, y, inner, inner, end, start)
+	path.Close()
+	return path.End()
+}
+
+// AbsAnnulus makes a filled annular sector (a wedge of a ring) centered at (x, y),
+// between radii inner and outer, through angles start and end.
+// A sector spanning a full circle makes a ring.
+func (c *Canvas) AbsAnnulus(x, y, inner, outer float32, start, end float64, fillcolor color.NRGBA) {
+	paint.FillShape(c.Context.Ops, fillcolor, clip.Outline{Path: c.annulusPath(x, y, inner, outer, start, end)}.Op())
+}
+
+// AbsPatternAnnulus fills an annular sector centered at (x, y) with a pattern
+func (c *Canvas) AbsPatternAnnulus(x, y, inner, outer float32, start, end float64, p Pattern) {
+	c.absPatternFill(c.annulusPath(x, y, inner, outer, start, end), f32.Pt(x-outer, y-outer), f32.Pt(x+outer, y+outer), p)
+}
+
+// EllipticalArcLine makes a stroked elliptical arc, using percentage-based measures
+// center is (x, y), radii (rx, ry), the arc begins at angle a1, and ends at a2.
+// The arc is stroked with the specified stroke size and color
+func (c *Canvas) EllipticalArcLine(x, y, rx, ry float32, a1, a2 float64, size float32, strokecolor color.NRGBA) {
+	x, y = dimen(x, y, c.Width, c.Height)
+	// the angles are negated, so that they increase counter-clockwise
+	c.AbsEllipticalArcLine(x, y, pct(rx, c.Width), pct(ry, c.Height), -a1, -arcSweep(a1, a2), pct(size, c.Width), strokecolor)
+}
+
+// Annulus makes a filled annular sector, using percentage-based measures
+// center is (x, y), between radii inner and outer, the sector begins at angle a1, and ends at a2.
+// As with Arc, a sector spanning a full circle makes a ring.
+func (c *Canvas) Annulus(x, y, inner, outer float32, a1, a2 float64, fillcolor color.NRGBA) {
+	x, y = dimen(x, y, c.Width, c.Height)
+	c.AbsAnnulus(x, y, pct(inner, c.Width), pct(outer, c.Width), a1, a2, fillcolor)
+}
+
+// PatternAnnulus fills an annular sector with a pattern, using percentage-based measures
+func (c *Canvas) PatternAnnulus(x, y, inner, outer float32, a1, a2 float64, p Pattern) {
+	x, y = dimen(x, y, c.Width, c.Height)
+	c.AbsPatternAnnulus(x, y, pct(inner, c.Width), pct(outer, c.Width), a1, a2, p)
+}
@@ -0,0 +1,283 @@
+package giocanvas
+
+import (
+	"image/color"
+	"math"
+
+	"gioui.org/f32"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+)
+
+// Arrows and connectors. Heads are drawn in pixels, oriented along the tangent
+// at each end of the path, and the path is shortened so that it does not
+// show past the head.
+
+// ArrowHead is the shape drawn at the end of an arrow
+type ArrowHead int
+
+const (
+	NoHead      ArrowHead = iota
+	OpenHead              // two strokes meeting at the tip
+	FilledHead            // a filled triangle
+	DiamondHead           // a filled diamond
+	CircleHead            // a filled circle
+	BarHead               // a stroke across the end
+)
+
+// ArrowStyle describes the heads of an arrow
+type ArrowStyle struct {
+	Start, End ArrowHead
+	Size       float32 // head length, as a percentage of the canvas width; if zero, four times the line width
+}
+
+// cubic is a cubic bezier curve in pixels
+type cubic [4]f32.Point
+
+// at returns the point at t (0-1) along b
+func (b cubic) at(t float32) f32.Point {
+	u := 1 - t
+	return b[0].Mul(u * u * u).Add(b[1].Mul(3 * u * u * t)).Add(b[2].Mul(3 * u * t * t)).Add(b[3].Mul(t * t * t))
+}
+
+// split divides b at t, using de Casteljau's algorithm
+func (b cubic) split(t float32) (cubic, cubic) {
+	lerp := func(p, q f32.Point) f32.Point { return p.Add(q.Sub(p).Mul(t)) }
+	p01, p12, p23 := lerp(b[0], b[1]), lerp(b[1], b[2]), lerp(b[2], b[3])
+	p012, p123 := lerp(p01, p12), lerp(p12, p23)
+	m := lerp(p012, p123)
+	return cubic{b[0], p01, p012, m}, cubic{m, p123, p23, b[3]}
+}
+
+// distance returns the parameter of the point on b that is d from the end (or the start, if fromStart)
+func (b cubic) distance(d float32, fromStart bool) float32 {
+	end, lo, hi := b[3], float32(0), float32(1)
+	if fromStart {
+		end = b[0]
+	}
+	for i := 0; i < 32; i++ {
+		mid := (lo + hi) / 2
+		near := length(b.at(mid).Sub(end)) < d
+		if near != fromStart {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// quadcubic converts a quadratic bezier curve to a cubic one
+func quadcubic(p0, c, p1 f32.Point) cubic {
+	return cubic{p0, p0.Add(c.Sub(p0).Mul(2.0 / 3)), p1.Add(c.Sub(p1).Mul(2.0 / 3)), p1}
+}
+
+// length returns the length of vector p
+func length(p f32.Point) float32 {
+	return float32(math.Hypot(float64(p.X), float64(p.Y)))
+}
+
+// direction returns the first non-zero vector from the points in order, normalized
+func direction(tip f32.Point, from ...f32.Point) (f32.Point, bool) {
+	for _, p := range from {
+		v := tip.Sub(p)
+		if l := length(v); l > 1e-3 {
+			return v.Mul(1 / l), true
+		}
+	}
+	return f32.Point{}, false
+}
+
+// headLength returns the pixel length of the heads of a style, with line width size
+func (c *Canvas) headLength(style ArrowStyle, size float32) float32 {
+	if style.Size > 0 {
+		return pct(style.Size, c.Width)
+	}
+	return size * 4
+}
+
+// inset returns how far the path is shortened for a head of length l
+func inset(head ArrowHead, l, size float32) float32 {
+	switch head {
+	case OpenHead:
+		return size / 2
+	case FilledHead, DiamondHead:
+		return l * 0.75
+	case CircleHead:
+		return l / 2
+	}
+	return 0
+}
+
+// head draws an arrow head with its tip at tip, pointing along the unit vector dir
+func (c *Canvas) head(head ArrowHead, tip, dir f32.Point, l, size float32, fillcolor color.NRGBA) {
+	n := f32.Pt(-dir.Y, dir.X).Mul(l * 0.4)
+	back := tip.Sub(dir.Mul(l))
+	switch head {
+	case OpenHead:
+		c.absStrokedPath([]f32.Point{back.Add(n), tip, back.Sub(n)}, size, fillcolor)
+	case FilledHead:
+		c.absFillPath([]f32.Point{tip, back.Add(n), back.Sub(n)}, fillcolor)
+	case DiamondHead:
+		mid := tip.Sub(dir.Mul(l / 2))
+		c.absFillPath([]f32.Point{tip, mid.Add(n), back, mid.Sub(n)}, fillcolor)
+	case CircleHead:
+		center := tip.Sub(dir.Mul(l / 2))
+		c.AbsCircle(center.X, center.Y, l/2, fillcolor)
+	case BarHead:
+		c.absStrokedPath([]f32.Point{tip.Add(n), tip.Sub(n)}, size, fillcolor)
+	}
+}
+
+// absFillPath fills the polygon with vertices p
+func (c *Canvas) absFillPath(p []f32.Point, fillcolor color.NRGBA) {
+	path := new(clip.Path)
+	path.Begin(c.Context.Ops)
+	path.MoveTo(p[0])
+	for _, v := range p[1:] {
+		path.LineTo(v)
+	}
+	path.Close()
+	paint.FillShape(c.Context.Ops, fillcolor, clip.Outline{Path: path.End()}.Op())
+}
+
+// absStrokedPath strokes the open path through the points p
+func (c *Canvas) absStrokedPath(p []f32.Point, size float32, strokecolor color.NRGBA) {
+	path := new(clip.Path)
+	path.Begin(c.Context.Ops)
+	path.MoveTo(p[0])
+	for _, v := range p[1:] {
+		path.LineTo(v)
+	}
+	paint.FillShape(c.Context.Ops, strokecolor, clip.Stroke{Path: path.End(), Width: size}.Op())
+}
+
+// absCubicArrow draws the curve b with heads, in pixels
+func (c *Canvas) absCubicArrow(b cubic, size float32, style ArrowStyle, strokecolor color.NRGBA) {
+	l := c.headLength(style, size)
+	t0, t1 := float32(0), float32(1)
+	if style.Start != NoHead {
+		if dir, ok := direction(b[0], b[1], b[2], b[3]); ok {
+			c.head(style.Start, b[0], dir, l, size, strokecolor)
+			t0 = b.distance(inset(style.Start, l, size), true)
+		}
+	}
+	if style.End != NoHead {
+		if dir, ok := direction(b[3], b[2], b[1], b[0]); ok {
+			c.head(style.End, b[3], dir, l, size, strokecolor)
+			t1 = b.distance(inset(style.End, l, size), false)
+		}
+	}
+	if t1 <= t0 {
+		return
+	}
+	b, _ = b.split(t1)
+	_, b = b.split(t0 / t1)
+	path := new(clip.Path)
+	path.Begin(c.Context.Ops)
+	path.MoveTo(b[0])
+	path.CubeTo(b[1], b[2], b[3])
+	paint.FillShape(c.Context.Ops, strokecolor, clip.Stroke{Path: path.End(), Width: size}.Op())
+}
+
+// absPolylineArrow draws the path through the points p with heads, in pixels
+func (c *Canvas) absPolylineArrow(p []f32.Point, size float32, style ArrowStyle, strokecolor color.NRGBA) {
+	if len(p) < 2 {
+		return
+	}
+	p = append([]f32.Point(nil), p...)
+	l := c.headLength(style, size)
+	reversed := make([]f32.Point, len(p))
+	for i := range p {
+		reversed[i] = p[len(p)-1-i]
+	}
+	if dir, ok := direction(p[0], p[1:]...); ok && style.Start != NoHead {
+		c.head(style.Start, p[0], dir, l, size, strokecolor)
+		p[0] = p[0].Sub(dir.Mul(min32(inset(style.Start, l, size), length(p[1].Sub(p[0])))))
+	}
+	if dir, ok := direction(reversed[0], reversed[1:]...); ok && style.End != NoHead {
+		n := len(p) - 1
+		c.head(style.End, p[n], dir, l, size, strokecolor)
+		p[n] = p[n].Sub(dir.Mul(min32(inset(style.End, l, size), length(p[n].Sub(p[n-1])))))
+	}
+	c.absStrokedPath(p, size, strokecolor)
+}
+
+// AbsArrow makes a line from (x0, y0) to (x1, y1) with arrow heads
+func (c *Canvas) AbsArrow(x0, y0, x1, y1, size float32, style ArrowStyle, strokecolor color.NRGBA) {
+	c.absPolylineArrow([]f32.Point{f32.Pt(x0, y0), f32.Pt(x1, y1)}, size, style, strokecolor)
+}
+
+// AbsCubicArrow makes a cubic bezier curve with arrow heads,
+// starting at (x, y), control points at (cx1, cy1), (cx2, cy2), end point (ex, ey)
+func (c *Canvas) AbsCubicArrow(x, y, cx1, cy1, cx2, cy2, ex, ey, size float32, style ArrowStyle, strokecolor color.NRGBA) {
+	c.absCubicArrow(cubic{f32.Pt(x, y), f32.Pt(cx1, cy1), f32.Pt(cx2, cy2), f32.Pt(ex, ey)}, size, style, strokecolor)
+}
+
+// abspt converts a percentage-based point to pixels
+func (c *Canvas) abspt(x, y float32) f32.Point {
+	x, y = dimen(x, y, c.Width, c.Height)
+	return f32.Pt(x, y)
+}
+
+// Arrow makes a line from (x0, y0) to (x1, y1) with arrow heads, using percentage-based measures
+func (c *Canvas) Arrow(x0, y0, x1, y1, size float32, style ArrowStyle, strokecolor color.NRGBA) {
+	c.absPolylineArrow([]f32.Point{c.abspt(x0, y0), c.abspt(x1, y1)}, pct(size, c.Width), style, strokecolor)
+}
+
+// CurveArrow makes a quadratic bezier curve with arrow heads, using percentage-based measures,
+// starting at (x, y), control point at (cx, cy), end point (ex, ey)
+func (c *Canvas) CurveArrow(x, y, cx, cy, ex, ey, size float32, style ArrowStyle, strokecolor color.NRGBA) {
+	c.absCubicArrow(quadcubic(c.abspt(x, y), c.abspt(cx, cy), c.abspt(ex, ey)), pct(size, c.Width), style, strokecolor)
+}
+
+// CubeArrow makes a cubic bezier curve with arrow heads, using percentage-based measures,
+// starting at (x, y), control points at (cx1, cy1), (cx2, cy2), end point (ex, ey)
+func (c *Canvas) CubeArrow(x, y, cx1, cy1, cx2, cy2, ex, ey, size float32, style ArrowStyle, strokecolor color.NRGBA) {
+	b := cubic{c.abspt(x, y), c.abspt(cx1, cy1), c.abspt(cx2, cy2), c.abspt(ex, ey)}
+	c.absCubicArrow(b, pct(size, c.Width), style, strokecolor)
+}
+
+// connection returns the points where a connector leaves from and enters to, in pixels,
+// just outside the boxes, and the unit vectors pointing out of each box
+func (c *Canvas) connection(from, to Rect, gap float32) (s, sdir, e, edir f32.Point) {
+	a0, a1 := c.abspt(from.Min.X, from.Max.Y), c.abspt(from.Max.X, from.Min.Y)
+	b0, b1 := c.abspt(to.Min.X, to.Max.Y), c.abspt(to.Max.X, to.Min.Y)
+	ca, cb := a0.Add(a1).Mul(0.5), b0.Add(b1).Mul(0.5)
+	d := cb.Sub(ca)
+	switch {
+	case math.Abs(float64(d.X)) >= math.Abs(float64(d.Y)) && d.X >= 0: // left to right
+		return f32.Pt(a1.X+gap, ca.Y), f32.Pt(1, 0), f32.Pt(b0.X-gap, cb.Y), f32.Pt(-1, 0)
+	case math.Abs(float64(d.X)) >= math.Abs(float64(d.Y)): // right to left
+		return f32.Pt(a0.X-gap, ca.Y), f32.Pt(-1, 0), f32.Pt(b1.X+gap, cb.Y), f32.Pt(1, 0)
+	case d.Y >= 0: // downward
+		return f32.Pt(ca.X, a1.Y+gap), f32.Pt(0, 1), f32.Pt(cb.X, b0.Y-gap), f32.Pt(0, -1)
+	default: // upward
+		return f32.Pt(ca.X, a0.Y-gap), f32.Pt(0, -1), f32.Pt(cb.X, b1.Y+gap), f32.Pt(0, 1)
+	}
+}
+
+// ElbowConnector joins the boxes from and to with horizontal and vertical lines and arrow heads,
+// using percentage-based measures. The connector leaves and enters the sides facing each other.
+func (c *Canvas) ElbowConnector(from, to Rect, size float32, style ArrowStyle, strokecolor color.NRGBA) {
+	size = pct(size, c.Width)
+	s, sdir, e, _ := c.connection(from, to, size)
+	var p []f32.Point
+	if sdir.X != 0 {
+		mid := (s.X + e.X) / 2
+		p = []f32.Point{s, f32.Pt(mid, s.Y), f32.Pt(mid, e.Y), e}
+	} else {
+		mid := (s.Y + e.Y) / 2
+		p = []f32.Point{s, f32.Pt(s.X, mid), f32.Pt(e.X, mid), e}
+	}
+	c.absPolylineArrow(p, size, style, strokecolor)
+}
+
+// CurvedConnector joins the boxes from and to with a curve and arrow heads,
+// using percentage-based measures. The curve leaves and enters the sides facing each other at right angles.
+func (c *Canvas) CurvedConnector(from, to Rect, size float32, style ArrowStyle, strokecolor color.NRGBA) {
+	size = pct(size, c.Width)
+	s, sdir, e, edir := c.connection(from, to, size)
+	k := length(e.Sub(s)) / 2
+	c.absCubicArrow(cubic{s, s.Add(sdir.Mul(k)), e.Add(edir.Mul(k)), e}, size, style, strokecolor)
+}
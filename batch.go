@@ -0,0 +1,178 @@
+package giocanvas
+
+import (
+	"image/color"
+
+	"gioui.org/f32"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+)
+
+// Batched drawing. Each method draws many shapes of one color as a single path,
+// painted once, which is much faster than drawing the shapes one at a time.
+// The shapes are at the corresponding elements of the coordinate slices; the size
+// slices either have the same length, or a single element used for every shape.
+// Nothing is drawn if the lengths do not match.
+
+// batchLen returns the number of shapes in a batch with coordinates in x and y, and sizes,
+// or -1 if the lengths do not match
+func batchLen(x, y []float32, sizes ...[]float32) int {
+	n := len(x)
+	if len(y) != n {
+		return -1
+	}
+	for _, s := range sizes {
+		if len(s) != n && len(s) != 1 {
+			return -1
+		}
+	}
+	return n
+}
+
+// batchValue returns the i-th element of s, or its only element
+func batchValue(s []float32, i int) float32 {
+	if len(s) == 1 {
+		return s[0]
+	}
+	return s[i]
+}
+
+// addCircle adds a circle centered at (x, y), with radius r, to a path
+func addCircle(path *clip.Path, x, y, r float32) {
+	const k = 0.551915024494 // http://spencermortensen.com/articles/bezier-circle/
+	o := f32.Pt(x, y)
+	path.MoveTo(o.Add(f32.Pt(r, 0)))
+	path.CubeTo(o.Add(f32.Pt(r, r*k)), o.Add(f32.Pt(r*k, r)), o.Add(f32.Pt(0, r)))      // SE
+	path.CubeTo(o.Add(f32.Pt(-r*k, r)), o.Add(f32.Pt(-r, r*k)), o.Add(f32.Pt(-r, 0)))   // SW
+	path.CubeTo(o.Add(f32.Pt(-r, -r*k)), o.Add(f32.Pt(-r*k, -r)), o.Add(f32.Pt(0, -r))) // NW
+	path.CubeTo(o.Add(f32.Pt(r*k, -r)), o.Add(f32.Pt(r, -r*k)), o.Add(f32.Pt(r, 0)))    // NE
+	path.Close()
+}
+
+// addRect adds a rectangle with its upper left corner at (x, y), and dimensions (w, h), to a path
+func addRect(path *clip.Path, x, y, w, h float32) {
+	path.MoveTo(f32.Pt(x, y))
+	path.LineTo(f32.Pt(x+w, y))
+	path.LineTo(f32.Pt(x+w, y+h))
+	path.LineTo(f32.Pt(x, y+h))
+	path.Close()
+}
+
+// AbsCircles makes filled circles centered at (x[i], y[i]), with radii r
+func (c *Canvas) AbsCircles(x, y, r []float32, fillcolor color.NRGBA) {
+	n := batchLen(x, y, r)
+	if n <= 0 {
+		return
+	}
+	path := new(clip.Path)
+	path.Begin(c.Context.Ops)
+	for i := 0; i < n; i++ {
+		addCircle(path, x[i], y[i], batchValue(r, i))
+	}
+	paint.FillShape(c.Context.Ops, fillcolor, clip.Outline{Path: path.End()}.Op())
+}
+
+// AbsRects makes filled rectangles with upper left corners at (x[i], y[i]), and dimensions (w, h)
+func (c *Canvas) AbsRects(x, y, w, h []float32, fillcolor color.NRGBA) {
+	n := batchLen(x, y, w, h)
+	if n <= 0 {
+		return
+	}
+	path := new(clip.Path)
+	path.Begin(c.Context.Ops)
+	for i := 0; i < n; i++ {
+		addRect(path, x[i], y[i], batchValue(w, i), batchValue(h, i))
+	}
+	paint.FillShape(c.Context.Ops, fillcolor, clip.Outline{Path: path.End()}.Op())
+}
+
+// AbsLines makes lines from (x0[i], y0[i]) to (x1[i], y1[i]), all with the same size
+func (c *Canvas) AbsLines(x0, y0, x1, y1 []float32, size float32, strokecolor color.NRGBA) {
+	n := batchLen(x0, y0)
+	if n <= 0 || batchLen(x1, y1) != n {
+		return
+	}
+	path := new(clip.Path)
+	path.Begin(c.Context.Ops)
+	for i := 0; i < n; i++ {
+		path.MoveTo(f32.Pt(x0[i], y0[i]))
+		path.LineTo(f32.Pt(x1[i], y1[i]))
+	}
+	paint.FillShape(c.Context.Ops, strokecolor, clip.Stroke{Path: path.End(), Width: size}.Op())
+}
+
+// Circles makes filled circles, using percentage-based measures,
+// centered at (x[i], y[i]), with radii r. As the circles are one fill,
+// translucent circles that overlap are not darker where they overlap.
+func (c *Canvas) Circles(x, y, r []float32, fillcolor color.NRGBA) {
+	n := batchLen(x, y, r)
+	if n <= 0 {
+		return
+	}
+	px, py, pr := make([]float32, n), make([]float32, n), make([]float32, len(r))
+	for i := 0; i < n; i++ {
+		px[i], py[i] = dimen(x[i], y[i], c.Width, c.Height)
+	}
+	for i := range r {
+		pr[i] = pct(r[i], c.Width)
+	}
+	c.AbsCircles(px, py, pr, fillcolor)
+}
+
+// Rects makes filled rectangles, using percentage-based measures,
+// centered at (x[i], y[i]), sized at (w, h). As the rectangles are one fill,
+// translucent rectangles that overlap are not darker where they overlap.
+func (c *Canvas) Rects(x, y, w, h []float32, fillcolor color.NRGBA) {
+	n := batchLen(x, y, w, h)
+	if n <= 0 {
+		return
+	}
+	px, py := make([]float32, n), make([]float32, n)
+	pw, ph := make([]float32, len(w)), make([]float32, len(h))
+	for i := range w {
+		pw[i] = pct(w[i], c.Width)
+	}
+	for i := range h {
+		ph[i] = pct(h[i], c.Height)
+	}
+	for i := 0; i < n; i++ {
+		px[i], py[i] = dimen(x[i], y[i], c.Width, c.Height)
+		px[i] -= batchValue(pw, i) / 2
+		py[i] -= batchValue(ph, i) / 2
+	}
+	c.AbsRects(px, py, pw, ph, fillcolor)
+}
+
+// Squares makes filled squares, using percentage-based measures,
+// centered at (x[i], y[i]), with sides w. As with Square, accounts for screen aspect
+func (c *Canvas) Squares(x, y, w []float32, fillcolor color.NRGBA) {
+	n := batchLen(x, y, w)
+	if n <= 0 {
+		return
+	}
+	px, py, pw := make([]float32, n), make([]float32, n), make([]float32, len(w))
+	for i := range w {
+		pw[i] = pct(w[i], c.Height)
+	}
+	for i := 0; i < n; i++ {
+		px[i], py[i] = dimen(x[i], y[i], c.Width, c.Height)
+		px[i] -= batchValue(pw, i) / 2
+		py[i] -= batchValue(pw, i) / 2
+	}
+	c.AbsRects(px, py, pw, pw, fillcolor)
+}
+
+// Lines makes lines, using percentage-based measures,
+// from (x0[i], y0[i]) to (x1[i], y1[i]), all with the same size
+func (c *Canvas) Lines(x0, y0, x1, y1 []float32, size float32, strokecolor color.NRGBA) {
+	n := batchLen(x0, y0)
+	if n <= 0 || batchLen(x1, y1) != n {
+		return
+	}
+	px0, py0, px1, py1 := make([]float32, n), make([]float32, n), make([]float32, n), make([]float32, n)
+	for i := 0; i < n; i++ {
+		px0[i], py0[i] = dimen(x0[i], y0[i], c.Width, c.Height)
+		px1[i], py1[i] = dimen(x1[i], y1[i], c.Width, c.Height)
+	}
+	c.AbsLines(px0, py0, px1, py1, pct(size, c.Width), strokecolor)
+}
@@ -0,0 +1,103 @@
+package giocanvas
+
+import (
+	"gioui.org/io/system"
+	"golang.org/x/text/unicode/bidi"
+)
+
+// Text direction. Right-to-left text, such as Arabic or Hebrew, begins at the right:
+// the start of the text is at its right, and its end at its left, so that start and
+// end alignment follow the direction. The Gio text shaper shapes the text, and orders
+// runs of mixed direction within it. The fonts of a canvas must have the glyphs of the
+// script; the Go fonts, used by default, do not have Arabic or Hebrew glyphs.
+
+// TextDirection is the direction of text
+type TextDirection int
+
+const (
+	DirectionAuto TextDirection = iota // the direction of the first strongly directional character, left to right if there is none
+	LTR                                // left to right
+	RTL                                // right to left
+)
+
+// strongRTL reports whether s begins, ignoring characters without a strong direction,
+// with a right to left character, and whether it has a strongly directional character at all
+func strongRTL(s string) (rtl, strong bool) {
+	for _, r := range s {
+		p, _ := bidi.LookupRune(r)
+		switch p.Class() {
+		case bidi.L:
+			return false, true
+		case bidi.R, bidi.AL:
+			return true, true
+		}
+	}
+	return false, false
+}
+
+// rtl reports whether s is set right to left on the canvas
+func (c *Canvas) rtl(s string) bool {
+	switch c.Direction {
+	case LTR:
+		return false
+	case RTL:
+		return true
+	}
+	rtl, _ := strongRTL(s)
+	return rtl
+}
+
+// useDirection sets the direction of the text shaped in the canvas context,
+// returning a function that restores it
+func (c *Canvas) useDirection(rtl bool) func() {
+	dir := c.Context.Locale.Direction
+	c.Context.Locale.Direction = system.LTR
+	if rtl {
+		c.Context.Locale.Direction = system.RTL
+	}
+	return func() { c.Context.Locale.Direction = dir }
+}
+
+// visualOrder returns the order, from left to right, of the words of a line, in a paragraph
+// that is right to left if rtl is set. Words without a strong direction take the direction
+// of the paragraph; runs of words of the other direction keep their own order.
+func visualOrder(words []string, rtl bool) []int {
+	base := 0
+	if rtl {
+		base = 1
+	}
+	levels := make([]int, len(words))
+	order := make([]int, len(words))
+	max := base
+	for i, w := range words {
+		order[i] = i
+		levels[i] = base
+		switch r, strong := strongRTL(w); {
+		case strong && r && !rtl:
+			levels[i] = 1
+		case strong && !r && rtl:
+			levels[i] = 2
+		}
+		if levels[i] > max {
+			max = levels[i]
+		}
+	}
+	// reverse each run of words at a level or higher, from the highest level down to the lowest odd level
+	for level := max; level >= 1; level-- {
+		for i := 0; i < len(order); {
+			if levels[order[i]] < level {
+				i++
+				continue
+			}
+			j := i
+			for j < len(order) && levels[order[j]] >= level {
+				j++
+			}
+			for a, b := i, j-1; a < b; a, b = a+1, b-1 {
+				order[a], order[b] = order[b], order[a]
+			}
+			i = j
+		}
+	}
+	return order
+}
@@ -0,0 +1,265 @@
+package giocanvas
+
+import (
+	"math"
+	"sort"
+)
+
+// Polygon boolean operations. The edges of each polygon are split where they meet the
+// edges or vertices of the other, and the pieces bounding the result are kept: for a union,
+// the pieces of each polygon outside the other; for an intersection, those inside; and for
+// a difference, the pieces of a outside b, with those of b inside a reversed. Pieces along
+// edges the polygons share are kept once or not at all, by their directions. The pieces are
+// then joined into polygons. The polygons must be simple (their edges do not cross); results
+// are counterclockwise, holes are clockwise, and their vertices are vertices of the polygons
+// or points where their edges cross.
+
+// clipop is a boolean operation
+type clipop int
+
+const (
+	clipUnion clipop = iota
+	clipIntersection
+	clipDifference
+)
+
+// Union returns the polygons covering the area of either a or b
+func Union(a, b Polygon) []Polygon {
+	return boolop(a, b, clipUnion)
+}
+
+// Intersection returns the polygons covering the area of both a and b
+func Intersection(a, b Polygon) []Polygon {
+	return boolop(a, b, clipIntersection)
+}
+
+// Difference returns the polygons covering the area of a that is not in b
+func Difference(a, b Polygon) []Polygon {
+	return boolop(a, b, clipDifference)
+}
+
+// segment is a directed piece of an edge
+type segment struct {
+	p, q Point
+}
+
+// reverse returns the segment in the other direction
+func (s segment) reverse() segment {
+	return segment{s.q, s.p}
+}
+
+// mid returns the midpoint of the segment
+func (s segment) mid() Point {
+	return s.p.Add(s.q).Mul(0.5)
+}
+
+// cut is a point splitting an edge, at t along it
+type cut struct {
+	t float64
+	p Point
+}
+
+// boolop performs a boolean operation
+func boolop(a, b Polygon, op clipop) []Polygon {
+	if len(a) < 3 || len(b) < 3 {
+		switch {
+		case op == clipIntersection || len(a) < 3 && op == clipDifference:
+			return nil
+		case len(a) < 3 && len(b) < 3:
+			return nil
+		case len(a) < 3:
+			return []Polygon{ccw(b)}
+		default:
+			return []Polygon{ccw(a)}
+		}
+	}
+	a, b = ccw(a), ccw(b)
+	eps := tolerance(a, b)
+	b = snap(b, a, eps)
+	ca, cb := cuts(a, b, eps)
+	sa, sb := split(a, ca), split(b, cb)
+	inA, inB := make(map[segment]bool), make(map[segment]bool)
+	for _, s := range sa {
+		inA[s] = true
+	}
+	for _, s := range sb {
+		inB[s] = true
+	}
+
+	var keep []segment
+	for _, s := range sa {
+		switch {
+		case inB[s]: // an edge of both, in the same direction
+			if op != clipDifference {
+				keep = append(keep, s)
+			}
+		case inB[s.reverse()]: // an edge of both, in opposite directions
+			if op == clipDifference {
+				keep = append(keep, s)
+			}
+		case b.Contains(s.mid()) == (op == clipIntersection):
+			keep = append(keep, s)
+		}
+	}
+	for _, s := range sb {
+		if inA[s] || inA[s.reverse()] {
+			continue
+		}
+		in := a.Contains(s.mid())
+		switch {
+		case op == clipUnion && !in, op == clipIntersection && in:
+			keep = append(keep, s)
+		case op == clipDifference && in:
+			keep = append(keep, s.reverse())
+		}
+	}
+	return join(keep)
+}
+
+// tolerance returns the distance within which points of the polygons are taken to coincide
+func tolerance(a, b Polygon) float64 {
+	r := a.Bounds().Union(b.Bounds())
+	return 1e-6 * math.Max(math.Max(math.Abs(float64(r.Min.X)), math.Abs(float64(r.Max.X))),
+		math.Max(math.Max(math.Abs(float64(r.Min.Y)), math.Abs(float64(r.Max.Y))), 1e-3))
+}
+
+// snap moves the vertices of p that coincide with vertices of q onto them
+func snap(p, q Polygon, eps float64) Polygon {
+	out := make(Polygon, len(p))
+	for i, v := range p {
+		out[i] = v
+		for _, w := range q {
+			if float64(v.Dist(w)) <= eps {
+				out[i] = w
+				break
+			}
+		}
+	}
+	return out
+}
+
+// cuts finds the points splitting the edges of a and b: where edges cross,
+// and where a vertex of one polygon lies within an edge of the other
+func cuts(a, b Polygon, eps float64) (ca, cb [][]cut) {
+	ca, cb = make([][]cut, len(a)), make([][]cut, len(b))
+	for i := range a {
+		a1, a2 := a[i], a[(i+1)%len(a)]
+		for j := range b {
+			b1, b2 := b[j], b[(j+1)%len(b)]
+			if t, ok := within(b1, a1, a2, eps); ok {
+				ca[i] = append(ca[i], cut{t, b1})
+			}
+			if u, ok := within(a1, b1, b2, eps); ok {
+				cb[j] = append(cb[j], cut{u, a1})
+			}
+			p, t, u, ok := intersect(a1, a2, b1, b2)
+			if !ok || t <= 0 || t >= 1 || u <= 0 || u >= 1 {
+				continue
+			}
+			// crossings at a vertex are found above
+			if float64(p.Dist(a1)) <= eps || float64(p.Dist(a2)) <= eps || float64(p.Dist(b1)) <= eps || float64(p.Dist(b2)) <= eps {
+				continue
+			}
+			ca[i] = append(ca[i], cut{t, p})
+			cb[j] = append(cb[j], cut{u, p})
+		}
+	}
+	return ca, cb
+}
+
+// within reports whether p lies within the segment (s1, s2), away from its ends,
+// and where along it
+func within(p, s1, s2 Point, eps float64) (float64, bool) {
+	if float64(p.Dist(s1)) <= eps || float64(p.Dist(s2)) <= eps || float64(p.DistToSegment(s1, s2)) > eps {
+		return 0, false
+	}
+	dx, dy := float64(s2.X-s1.X), float64(s2.Y-s1.Y)
+	t := (float64(p.X-s1.X)*dx + float64(p.Y-s1.Y)*dy) / (dx*dx + dy*dy)
+	return t, t > 0 && t < 1
+}
+
+// split divides the edges of p at the cuts
+func split(p Polygon, cuts [][]cut) []segment {
+	var out []segment
+	for i := range p {
+		c := cuts[i]
+		sort.Slice(c, func(j, k int) bool { return c[j].t < c[k].t })
+		from := p[i]
+		for _, x := range append(c, cut{1, p[(i+1)%len(p)]}) {
+			if x.p != from {
+				out = append(out, segment{from, x.p})
+				from = x.p
+			}
+		}
+	}
+	return out
+}
+
+// join links segments end to end into polygons. The result is to the left of each segment, so
+// polygons are counterclockwise and holes clockwise. Where several segments continue from a point,
+// the one turning farthest to the left is followed, keeping polygons that touch at a vertex apart.
+func join(segs []segment) []Polygon {
+	from := make(map[Point][]int)
+	for i, s := range segs {
+		from[s.p] = append(from[s.p], i)
+	}
+	used := make([]bool, len(segs))
+	var result []Polygon
+	for i := range segs {
+		if used[i] {
+			continue
+		}
+		var poly Polygon
+		for cur := i; cur >= 0; {
+			used[cur] = true
+			s := segs[cur]
+			poly = append(poly, s.p)
+			if s.q == segs[i].p {
+				break
+			}
+			next, turn := -1, -math.Pi
+			for _, j := range from[s.q] {
+				if a := turnAngle(s, segs[j]); !used[j] && (next < 0 || a > turn) {
+					next, turn = j, a
+				}
+			}
+			cur = next
+		}
+		if poly = simplify(poly); len(poly) >= 3 {
+			result = append(result, poly)
+		}
+	}
+	return result
+}
+
+// turnAngle returns the angle turned going from s to t, counterclockwise positive
+func turnAngle(s, t segment) float64 {
+	d, e := s.q.Sub(s.p), t.q.Sub(t.p)
+	return math.Atan2(float64(d.X*e.Y-d.Y*e.X), float64(d.X*e.X+d.Y*e.Y))
+}
+
+// simplify removes the vertices of p that lie on a straight line between their neighbors
+func simplify(p Polygon) Polygon {
+	for changed := true; changed && len(p) >= 3; {
+		changed = false
+		for i := 0; i < len(p) && len(p) >= 3; i++ {
+			prev, next := p[(i+len(p)-1)%len(p)], p[(i+1)%len(p)]
+			dx, dy := float64(p[i].X-prev.X), float64(p[i].Y-prev.Y)
+			ex, ey := float64(next.X-p[i].X), float64(next.Y-p[i].Y)
+			if math.Abs(dx*ey-dy*ex) <= 1e-9*math.Hypot(dx, dy)*math.Hypot(ex, ey) && dx*ex+dy*ey >= 0 {
+				p = append(p[:i], p[i+1:]...)
+				changed = true
+				i--
+			}
+		}
+	}
+	return p
+}
+
+// ccw returns p with its vertices in counterclockwise order
+func ccw(p Polygon) Polygon {
+	if p.Clockwise() {
+		return p.Reverse()
+	}
+	return p
+}
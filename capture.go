@@ -0,0 +1,346 @@
+package giocanvas
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gioui.org/io/key"
+	"github.com/ajstarks/giocanvas/internal/offscreen"
+)
+
+// Frame capture. Screenshot makes an image of the drawing on a canvas, rendered offscreen.
+// A Recorder makes numbered PNG files, an animated GIF, or an animated PNG (APNG) from
+// a sequence of frames. Capture saves the frames shown in a window, as asked for
+// with keys or command line flags.
+
+// Screenshot returns an image of the drawing on the canvas
+func (c *Canvas) Screenshot() (*image.RGBA, error) {
+	return offscreen.Render(int(c.Width), int(c.Height), c.Context.Ops)
+}
+
+// SavePNG writes an image to a PNG file
+func SavePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// numbered returns a file name, replacing a %d verb in the name with n
+func numbered(name string, n int) string {
+	if strings.Contains(name, "%") {
+		return fmt.Sprintf(name, n)
+	}
+	return name
+}
+
+// recording formats
+const (
+	pngFrames = iota // numbered PNG files
+	gifAnim          // animated GIF
+	apngAnim         // animated PNG
+)
+
+// Recorder makes an animation from a sequence of frames
+type Recorder struct {
+	path   string
+	format int
+	delay  float64 // seconds per frame
+	bounds image.Rectangle
+	n      int
+	gif    gif.GIF
+	apng   [][]byte // the compressed frames
+}
+
+// NewRecorder makes a recorder writing to path, at a frame rate (frames per second; default 30).
+// The format follows from the name: a name with a %d verb makes numbered PNG files,
+// written as the frames are added; a .gif name, an animated GIF; and a .png or .apng name,
+// an animated PNG. Animations are written by Close.
+func NewRecorder(path string, framerate float64) (*Recorder, error) {
+	if framerate <= 0 {
+		framerate = 30
+	}
+	r := &Recorder{path: path, delay: 1 / framerate}
+	switch ext := strings.ToLower(filepath.Ext(path)); {
+	case strings.Contains(path, "%"):
+		r.format = pngFrames
+	case ext == ".gif":
+		r.format = gifAnim
+	case ext == ".png" || ext == ".apng":
+		r.format = apngAnim
+	default:
+		return nil, fmt.Errorf("%s: cannot record (use .gif, .png, .apng, or a name with %%d)", path)
+	}
+	return r, nil
+}
+
+// Len returns the number of frames added
+func (r *Recorder) Len() int {
+	return r.n
+}
+
+// Add adds a frame to the recording. Frames are clipped or padded to the size of the first.
+func (r *Recorder) Add(img image.Image) error {
+	b := img.Bounds()
+	if r.n == 0 {
+		r.bounds = image.Rect(0, 0, b.Dx(), b.Dy())
+	}
+	frame := image.NewNRGBA(r.bounds)
+	draw.Draw(frame, r.bounds, img, b.Min, draw.Src)
+	switch r.format {
+	case pngFrames:
+		if err := SavePNG(numbered(r.path, r.n), frame); err != nil {
+			return err
+		}
+	case gifAnim:
+		p := image.NewPaletted(r.bounds, palette.Plan9)
+		draw.FloydSteinberg.Draw(p, r.bounds, frame, image.Point{})
+		r.gif.Image = append(r.gif.Image, p)
+		r.gif.Delay = append(r.gif.Delay, int(math.Max(2, math.Round(r.delay*100)))) // browsers slow shorter delays
+	case apngAnim:
+		r.apng = append(r.apng, deflateFrame(frame))
+	}
+	r.n++
+	return nil
+}
+
+// AddCanvas adds the drawing on the canvas to the recording
+func (r *Recorder) AddCanvas(c *Canvas) error {
+	img, err := c.Screenshot()
+	if err != nil {
+		return err
+	}
+	return r.Add(img)
+}
+
+// Close writes an animation; numbered PNG files have already been written
+func (r *Recorder) Close() error {
+	if r.n == 0 || r.format == pngFrames {
+		return nil
+	}
+	f, err := os.Create(r.path)
+	if err != nil {
+		return err
+	}
+	if r.format == gifAnim {
+		err = gif.EncodeAll(f, &r.gif)
+	} else {
+		err = writeAPNG(f, r.bounds.Dx(), r.bounds.Dy(), r.delay, r.apng)
+	}
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// deflateFrame returns the compressed image data of an APNG frame,
+// using the Sub filter: each byte is stored less the one of the pixel to its left
+func deflateFrame(img *image.NRGBA) []byte {
+	var buf bytes.Buffer
+	z := zlib.NewWriter(&buf)
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	row := make([]byte, 1+4*w)
+	row[0] = 1 // Sub
+	for y := 0; y < h; y++ {
+		pix := img.Pix[y*img.Stride : y*img.Stride+4*w]
+		for i := range pix {
+			if i < 4 {
+				row[1+i] = pix[i]
+			} else {
+				row[1+i] = pix[i] - pix[i-4]
+			}
+		}
+		z.Write(row)
+	}
+	z.Close()
+	return buf.Bytes()
+}
+
+// writeAPNG writes frames, compressed by deflateFrame, as an animated PNG that repeats forever
+func writeAPNG(w io.Writer, width, height int, delay float64, frames [][]byte) error {
+	bw := bufio.NewWriter(w)
+	chunk := func(name string, data ...[]byte) {
+		var n int
+		for _, d := range data {
+			n += len(d)
+		}
+		crc := crc32.NewIEEE()
+		binary.Write(bw, binary.BigEndian, uint32(n))
+		io.WriteString(bw, name)
+		io.WriteString(crc, name)
+		for _, d := range data {
+			bw.Write(d)
+			crc.Write(d)
+		}
+		binary.Write(bw, binary.BigEndian, crc.Sum32())
+	}
+	be := binary.BigEndian
+
+	io.WriteString(bw, "\x89PNG\r\n\x1a\n")
+	ihdr := make([]byte, 13)
+	be.PutUint32(ihdr[0:], uint32(width))
+	be.PutUint32(ihdr[4:], uint32(height))
+	ihdr[8], ihdr[9] = 8, 6 // 8 bits per sample, RGBA
+	chunk("IHDR", ihdr)
+	actl := make([]byte, 8)
+	be.PutUint32(actl[0:], uint32(len(frames))) // the number of plays is zero: repeat forever
+	chunk("acTL", actl)
+
+	ms := uint16(math.Min(math.Round(delay*1000), math.MaxUint16))
+	var seq uint32
+	for i, f := range frames {
+		fctl := make([]byte, 26) // offset, dispose and blend operations are zero
+		be.PutUint32(fctl[0:], seq)
+		be.PutUint32(fctl[4:], uint32(width))
+		be.PutUint32(fctl[8:], uint32(height))
+		be.PutUint16(fctl[20:], ms)
+		be.PutUint16(fctl[22:], 1000)
+		chunk("fcTL", fctl)
+		seq++
+		if i == 0 {
+			chunk("IDAT", f)
+			continue
+		}
+		fdat := make([]byte, 4)
+		be.PutUint32(fdat, seq)
+		chunk("fdAT", fdat, f)
+		seq++
+	}
+	chunk("IEND")
+	return bw.Flush()
+}
+
+// Capture saves the frames shown in a window. Ctrl+S saves the current frame as a PNG,
+// and Ctrl+R starts and stops a recording. Pass key events to Key, and each canvas
+// to Frame after drawing and before it is shown; Close finishes a recording.
+type Capture struct {
+	Snapshot  string  // snapshot file; a %d verb is replaced by the snapshot number (default "snapshot-%d.png")
+	Record    string  // recording file, as for NewRecorder (default "record.gif")
+	FrameRate float64 // playback frame rate of recordings (default 30)
+	Frames    int     // number of frames to record; zero records until stopped
+	nsnap     int
+	snap      bool // save the next frame
+	start     bool // start recording with the next frame
+	stop      bool // stop recording before the next frame
+	rec       *Recorder
+}
+
+// Flags adds the capture flags to a flag set: -snapshot saves the first frame, -record records from
+// the first frame, -fps sets the frame rate of recordings, and -frames the number of frames to record
+func (c *Capture) Flags(fs *flag.FlagSet) {
+	fs.Func("snapshot", "save the first frame to a PNG `file`", func(s string) error {
+		c.Snapshot, c.snap = s, true
+		return nil
+	})
+	fs.Func("record", "record to a `file` (.gif, .png or .apng animation, or numbered PNG files with %d)", func(s string) error {
+		c.Record, c.start = s, true
+		return nil
+	})
+	fs.Float64Var(&c.FrameRate, "fps", 30, "frame rate of recordings")
+	fs.IntVar(&c.Frames, "frames", 0, "number of frames to record (0: until stopped)")
+}
+
+// Recording reports whether frames are being recorded
+func (c *Capture) Recording() bool {
+	return c.rec != nil
+}
+
+// Key handles the capture keys, reporting whether the event was one of them
+func (c *Capture) Key(e key.Event) bool {
+	if e.State != key.Press || !e.Modifiers.Contain(key.ModCtrl) {
+		return false
+	}
+	switch e.Name {
+	case "S":
+		c.snap = true
+	case "R":
+		if c.rec != nil {
+			c.stop = true
+		} else {
+			c.start = true
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// Frame saves the drawing on the canvas, if asked for
+func (c *Capture) Frame(canvas *Canvas) error {
+	if c.stop {
+		c.stop = false
+		if err := c.Close(); err != nil {
+			return err
+		}
+	}
+	if !c.snap && !c.start && c.rec == nil {
+		return nil
+	}
+	img, err := canvas.Screenshot()
+	if err != nil {
+		return err
+	}
+	if c.snap {
+		c.snap = false
+		name := c.Snapshot
+		if name == "" {
+			name = "snapshot-%d.png"
+		}
+		c.nsnap++
+		if err := SavePNG(numbered(name, c.nsnap), img); err != nil {
+			return err
+		}
+	}
+	if c.start {
+		c.start = false
+		name := c.Record
+		if name == "" {
+			name = "record.gif"
+		}
+		if c.rec, err = NewRecorder(name, c.FrameRate); err != nil {
+			return err
+		}
+	}
+	if c.rec == nil {
+		return nil
+	}
+	if err := c.rec.Add(img); err != nil {
+		c.rec = nil
+		return err
+	}
+	if c.Frames > 0 && c.rec.Len() >= c.Frames {
+		return c.Close()
+	}
+	return nil
+}
+
+// Close finishes the recording, if there is one
+func (c *Capture) Close() error {
+	if c.rec == nil {
+		return nil
+	}
+	err := c.rec.Close()
+	c.rec = nil
+	return err
+}
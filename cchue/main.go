@@ -12,11 +12,6 @@ import (
 	"github.com/ajstarks/giocanvas"
 )
 
-// hsv makes a color given hue, saturation, value
-func hsv(hue, sat, value int) color.NRGBA {
-	return giocanvas.ColorLookup(fmt.Sprintf("hsv(%d,%d,%d)", hue, sat, value))
-}
-
 // planet makes circles around a central point
 func planet(canvas *giocanvas.Canvas, x, y, size, radius, a1, a2, steps float32, color color.NRGBA) {
 	for t := a1; t < a2; t += steps {
@@ -26,7 +21,7 @@ func planet(canvas *giocanvas.Canvas, x, y, size, radius, a1, a2, steps float32,
 }
 
 // cchue makes a concentric circle pattern with varying hue
-func cchue(canvas *giocanvas.Canvas, r, step float32, starthue int, bgcolor color.NRGBA) {
+func cchue(canvas *giocanvas.Canvas, r, step float32, starthue float64, bgcolor color.NRGBA) {
 	var cstep, c, halfstep, csize float32
 	cstep = 0.5
 	c = 0.5
@@ -35,36 +30,36 @@ func cchue(canvas *giocanvas.Canvas, r, step float32, starthue int, bgcolor colo
 	hue := starthue
 
 	canvas.Background(bgcolor)
-	canvas.Circle(50, 50, csize, hsv(hue, 100, 100))
-	planet(canvas, 50, 50, c, r, 0, 360, step, hsv(hue, 100, 100))
+	canvas.Circle(50, 50, csize, giocanvas.HSV(hue, 100, 100))
+	planet(canvas, 50, 50, c, r, 0, 360, step, giocanvas.HSV(hue, 100, 100))
 	r += 2
 	c += cstep
 	hue += 7
-	planet(canvas, 50, 50, c, r, halfstep, 360, step, hsv(hue, 100, 100))
+	planet(canvas, 50, 50, c, r, halfstep, 360, step, giocanvas.HSV(hue, 100, 100))
 	r += 3
 	c += cstep
 	hue += 7
-	planet(canvas, 50, 50, c, r, 0, 360, step, hsv(hue, 100, 100))
+	planet(canvas, 50, 50, c, r, 0, 360, step, giocanvas.HSV(hue, 100, 100))
 	r += 4
 	c += cstep
 	hue += 7
-	planet(canvas, 50, 50, c, r, halfstep, 360, step, hsv(hue, 100, 100))
+	planet(canvas, 50, 50, c, r, halfstep, 360, step, giocanvas.HSV(hue, 100, 100))
 	r += 5
 	c += cstep
 	hue += 7
-	planet(canvas, 50, 50, c, r, 0, 360, step, hsv(hue, 100, 100))
+	planet(canvas, 50, 50, c, r, 0, 360, step, giocanvas.HSV(hue, 100, 100))
 	r += 6
 	c += cstep
 	hue += 7
-	planet(canvas, 50, 50, c, r, halfstep, 360, step, hsv(hue, 100, 100))
+	planet(canvas, 50, 50, c, r, halfstep, 360, step, giocanvas.HSV(hue, 100, 100))
 	r += 8
 	hue += 7
-	planet(canvas, 50, 50, 3.5, r, 0, 360, step, hsv(hue, 100, 100))
+	planet(canvas, 50, 50, 3.5, r, 0, 360, step, giocanvas.HSV(hue, 100, 100))
 
 	var t float32
 	for t = 0.0; t <= 360; t += step {
 		px, py := canvas.PolarDegrees(50, 50, r, t)
-		planet(canvas, px, py, 0.5, 5, 0, 360, 30, hsv(starthue, 100, 100))
+		planet(canvas, px, py, 0.5, 5, 0, 360, 30, giocanvas.HSV(starthue, 100, 100))
 	}
 }
 
@@ -28,6 +28,8 @@ type ChartBox struct {
 	Top, Bottom, Left, Right float64
 	Minvalue, Maxvalue       float64
 	Zerobased                bool
+	Radius                   float64      // corner radius of the frame
+	Patterns                 []gc.Pattern // if set, pattern fills for bars, areas and pie wedges
 }
 
 const (
@@ -133,6 +135,28 @@ func dottedvline(canvas *gc.Canvas, x, y1, y2, dotsize, step float32, color colo
 	}
 }
 
+// pattern returns the fill pattern for item i, if there are patterns;
+// marks without a color use fillcolor
+func (c *ChartBox) pattern(i int, fillcolor color.NRGBA) (gc.Pattern, bool) {
+	if len(c.Patterns) == 0 {
+		return gc.Pattern{}, false
+	}
+	p := c.Patterns[i%len(c.Patterns)]
+	if p.Color == (color.NRGBA{}) {
+		p.Color = fillcolor
+	}
+	return p, true
+}
+
+// patternbar fills a bar from (x1, y1) to (x2, y2), of thickness sw, with a pattern
+func patternbar(canvas *gc.Canvas, x1, y1, x2, y2, sw float32, p gc.Pattern) {
+	if y1 == y2 { // horizontal
+		canvas.PatternCornerRect(float32(math.Min(float64(x1), float64(x2))), y1+(sw/2), float32(math.Abs(float64(x2-x1))), sw, p)
+		return
+	}
+	canvas.PatternCornerRect(x1-(sw/2), float32(math.Max(float64(y1), float64(y2))), sw, float32(math.Abs(float64(y2-y1))), p)
+}
+
 // MinMax set the minimum and maximum value for charting a dataset
 func (c *ChartBox) MinMax(minval, maxval float64) {
 	c.Minvalue = minval
@@ -148,6 +172,10 @@ func (c *ChartBox) Bar(canvas *gc.Canvas, size float64) {
 	for i, d := range c.Data {
 		x := float32(gc.MapRange(float64(i), 0, dlen, c.Left, c.Right))
 		y := float32(gc.MapRange(d.value, ymin, c.Maxvalue, c.Bottom, c.Top))
+		if p, ok := c.pattern(i, c.Color); ok {
+			patternbar(canvas, x, bottom, x, y, lw, p)
+			continue
+		}
 		drawline(canvas, x, bottom, x, y, lw, c.Color)
 	}
 }
@@ -160,11 +188,15 @@ func (c *ChartBox) HBar(canvas *gc.Canvas, size, linespacing, textsize float64,
 	ts3 := ts / 3
 	ls := float32(linespacing)
 	xmin := zerobase(c.Zerobased, c.Minvalue)
-	for _, d := range c.Data {
+	for i, d := range c.Data {
 		ty := y - ts3
 		canvas.EText(cl-2, ty, ts, d.label, labelcolor)
 		x2 := float32(gc.MapRange(d.value, xmin, c.Maxvalue, c.Left, c.Right))
-		drawline(canvas, cl, y, x2, y, float32(size), c.Color)
+		if p, ok := c.pattern(i, c.Color); ok {
+			patternbar(canvas, cl, y, x2, y, float32(size), p)
+		} else {
+			drawline(canvas, cl, y, x2, y, float32(size), c.Color)
+		}
 		if len(valuefmt) > 0 {
 			canvas.Text(x2+ts, ty, ts*0.75, fmt.Sprintf(valuefmt, d.value), gc.ColorLookup(valuecolor))
 		}
@@ -234,6 +266,10 @@ func (c *ChartBox) Area(canvas *gc.Canvas, opacity float64) {
 	}
 	vcolor := c.Color
 	vcolor.A = uint8(255.0 * (opacity / 100))
+	if p, ok := c.pattern(0, vcolor); ok {
+		canvas.PatternPolygon(ax, ay, p)
+		return
+	}
 	canvas.Polygon(ax, ay, vcolor)
 }
 
@@ -262,21 +298,37 @@ func datasum(data []NameValue) float64 {
 
 // Pie makes a pie chart
 func (c *ChartBox) Pie(canvas *gc.Canvas, r float64) {
-	px, py, pr := float32(c.Left+r), float32(c.Top-r), float32(r)
+	c.Donut(canvas, r, 0)
+}
+
+// Donut makes a donut chart: a pie chart with a hole of radius inner
+func (c *ChartBox) Donut(canvas *gc.Canvas, r, inner float64) {
+	px, py, pr, ir := float32(c.Left+r), float32(c.Top-r), float32(r), float32(inner)
 	sum := datasum(c.Data)
 	a1 := 0.0
 	labelr := pr + 10
 	ts := pr / 20
-	for _, d := range c.Data {
+	for i, d := range c.Data {
 		fillcolor := gc.ColorLookup(d.note)
 		pct := (d.value / sum)
 		a2 := (fullcircle * pct) + a1
 		mid := fullcircle - (a1 + (a2-a1)/2)
-		canvas.Arc(px, py, pr, a1, a2, fillcolor)
+		p, ok := c.pattern(i, fillcolor)
+		switch {
+		case ir > 0 && ok:
+			canvas.PatternAnnulus(px, py, ir, pr, a1, a2, p)
+		case ir > 0:
+			canvas.Annulus(px, py, ir, pr, a1, a2, fillcolor)
+		case ok:
+			canvas.PatternArc(px, py, pr, a1, a2, p)
+		default:
+			canvas.Arc(px, py, pr, a1, a2, fillcolor)
+		}
 		tx, ty := canvas.Polar(px, py, labelr, float32(mid))
 		lx, ly := canvas.Polar(px, py, labelr-ts, float32(mid))
+		ix, iy := canvas.Polar(px, py, ir, float32(mid))
 		canvas.CText(tx, ty, ts, fmt.Sprintf("%s (%.2f%%)", d.label, pct*100), fillcolor)
-		canvas.Line(px, py, lx, ly, 0.1, fillcolor)
+		canvas.Line(ix, iy, lx, ly, 0.1, fillcolor)
 		a1 = a2
 	}
 }
@@ -284,18 +336,18 @@ func (c *ChartBox) Pie(canvas *gc.Canvas, r float64) {
 // dotgrid makes a grid 10x10 grid of dots colored by value
 func dotgrid(canvas *gc.Canvas, x, y, left, step float32, n int, fillcolor color.NRGBA) (float32, float32) {
 	edge := (((step * 0.3) + step) * 7) + left
+	xs, ys := make([]float32, 0, n), make([]float32, 0, n)
 	for i := 0; i < n; i++ {
 		if x > edge {
 			x = left
 			y -= step
 		}
-		op := fillcolor.A
-		canvas.Circle(x, y, step*0.3, fillcolor)
-		fillcolor.A = op - 30
-		canvas.Square(x, y, step*0.9, fillcolor)
-		fillcolor.A = op
+		xs, ys = append(xs, x), append(ys, y)
 		x += step
 	}
+	canvas.Circles(xs, ys, []float32{step * 0.3}, fillcolor)
+	fillcolor.A -= 30
+	canvas.Squares(xs, ys, []float32{step * 0.9}, fillcolor)
 	return x, y
 }
 
@@ -402,12 +454,23 @@ func (c *ChartBox) CTitle(canvas *gc.Canvas, size, offset float64) {
 	canvas.CText(float32(midx), float32(c.Top+offset), float32(size), c.Title, c.Color)
 }
 
-// Frame makes a filled frame with the specified opacity (0-100)
+// YTitle makes a title for the y axis, reading from bottom to top, centered
+// along the left of the chart, at offset from the left
+func (c *ChartBox) YTitle(canvas *gc.Canvas, size, offset float64, title string) {
+	midy := c.Bottom + ((c.Top - c.Bottom) / 2)
+	canvas.VTextMid(float32(c.Left-offset), float32(midy), float32(size), title, c.Color, gc.RotateUp)
+}
+
+// Frame makes a filled frame with the specified opacity (0-100), with rounded corners if Radius > 0
 func (c *ChartBox) Frame(canvas *gc.Canvas, op float64) {
 	if op <= 0 {
 		return
 	}
 	frameColor := c.Color
 	frameColor.A = uint8((op / 100) * 255.0)
+	if c.Radius > 0 {
+		canvas.RoundedRect(float32(c.Left), float32(c.Top), float32(c.Right-c.Left), float32(c.Top-c.Bottom), gc.Radius(float32(c.Radius)), frameColor)
+		return
+	}
 	canvas.CornerRect(float32(c.Left), float32(c.Top), float32(c.Right-c.Left), float32(c.Top-c.Bottom), frameColor)
 }
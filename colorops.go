@@ -0,0 +1,222 @@
+package giocanvas
+
+import (
+	"image/color"
+	"math"
+	"sort"
+)
+
+// Color manipulation. Lightness and saturation changes, and mixing
+// are done in the perceptual OKLab/OKLCH color spaces (https://bottosson.github.io/posts/oklab/)
+
+// HSV returns the color for hue (0-360), saturation (0-100) and value (0-100)
+func HSV(hue, sat, value float64) color.NRGBA {
+	r, g, b := hsv2rgb(math.Mod(math.Mod(hue, 360)+360, 360), clamp(sat, 0, 100), clamp(value, 0, 100))
+	return color.NRGBA{r, g, b, 255}
+}
+
+// HSL returns the color for hue (0-360), saturation (0-100) and lightness (0-100)
+func HSL(hue, sat, light float64) color.NRGBA {
+	r, g, b := hsl2rgb(math.Mod(math.Mod(hue, 360)+360, 360), clamp(sat, 0, 100)/100, clamp(light, 0, 100)/100)
+	return nrgba(r, g, b, 1)
+}
+
+// Lighten increases the lightness of a color by amount (0-1)
+func Lighten(c color.NRGBA, amount float64) color.NRGBA {
+	l, ch, h := OKLCH(c)
+	return FromOKLCH(clamp(l+amount, 0, 1), ch, h, c.A)
+}
+
+// Darken decreases the lightness of a color by amount (0-1)
+func Darken(c color.NRGBA, amount float64) color.NRGBA {
+	return Lighten(c, -amount)
+}
+
+// Saturate increases the chroma of a color by a fraction (1 doubles it)
+func Saturate(c color.NRGBA, amount float64) color.NRGBA {
+	l, ch, h := OKLCH(c)
+	return FromOKLCH(l, math.Max(0, ch*(1+amount)), h, c.A)
+}
+
+// Desaturate decreases the chroma of a color by a fraction (1 makes it gray)
+func Desaturate(c color.NRGBA, amount float64) color.NRGBA {
+	return Saturate(c, -clamp(amount, 0, 1))
+}
+
+// Complement returns the color with the opposite hue
+func Complement(c color.NRGBA) color.NRGBA {
+	h, s, l := rgb2hsl(c)
+	r, g, b := hsl2rgb(math.Mod(h+180, 360), s, l)
+	return nrgba(r, g, b, float64(c.A)/255)
+}
+
+// Mix interpolates between two colors in OKLab; t=0 is c1, t=1 is c2
+func Mix(c1, c2 color.NRGBA, t float64) color.NRGBA {
+	l1, a1, b1 := OKLab(c1)
+	l2, a2, b2 := OKLab(c2)
+	r, g, b := oklab2rgb(lerp(l1, l2, t), lerp(a1, a2, t), lerp(b1, b2, t))
+	return nrgba(r, g, b, lerp(float64(c1.A), float64(c2.A), t)/255)
+}
+
+// MixLCH interpolates between two colors in OKLCH, taking the shorter way around the hue circle.
+// t=0 is c1, t=1 is c2
+func MixLCH(c1, c2 color.NRGBA, t float64) color.NRGBA {
+	l1, ch1, h1 := OKLCH(c1)
+	l2, ch2, h2 := OKLCH(c2)
+	// gray has no hue, use the other one
+	if ch1 < 1e-4 {
+		h1 = h2
+	}
+	if ch2 < 1e-4 {
+		h2 = h1
+	}
+	dh := math.Mod(h2-h1+540, 360) - 180
+	alpha := uint8(math.Round(lerp(float64(c1.A), float64(c2.A), t)))
+	return FromOKLCH(lerp(l1, l2, t), lerp(ch1, ch2, t), h1+dh*t, alpha)
+}
+
+// Interpolate returns the color at t (0-1) along evenly spaced color stops, mixing in OKLab.
+// A NaN t is the first stop.
+func Interpolate(stops []color.NRGBA, t float64) color.NRGBA {
+	n := len(stops)
+	switch {
+	case n == 0:
+		return color.NRGBA{}
+	case n == 1 || t <= 0 || math.IsNaN(t):
+		return stops[0]
+	case t >= 1:
+		return stops[n-1]
+	}
+	p := t * float64(n-1)
+	i := int(p)
+	return Mix(stops[i], stops[i+1], p-float64(i))
+}
+
+// InterpolateAt returns the color at t (0-1) along color stops placed at ascending offsets (0-1).
+// Repeated offsets make sharp changes of color. Without an offset for each stop, the stops are evenly spaced.
+func InterpolateAt(stops []color.NRGBA, offsets []float64, t float64) color.NRGBA {
+	n := len(stops)
+	if len(offsets) != n || n < 2 {
+		return Interpolate(stops, t)
+	}
+	switch {
+	case t <= offsets[0] || math.IsNaN(t):
+		return stops[0]
+	case t >= offsets[n-1]:
+		return stops[n-1]
+	}
+	j := sort.Search(n, func(j int) bool { return offsets[j] > t })
+	i := j - 1
+	return Mix(stops[i], stops[j], unitmap(t, offsets[i], offsets[j]))
+}
+
+// Luminance returns the relative luminance (0-1) of a color, as defined by WCAG 2
+func Luminance(c color.NRGBA) float64 {
+	r, g, b := linear(float64(c.R)/255), linear(float64(c.G)/255), linear(float64(c.B)/255)
+	return 0.2126*r + 0.7152*g + 0.0722*b
+}
+
+// Contrast returns the WCAG 2 contrast ratio (1-21) between two colors
+func Contrast(c1, c2 color.NRGBA) float64 {
+	l1, l2 := Luminance(c1), Luminance(c2)
+	if l1 < l2 {
+		l1, l2 = l2, l1
+	}
+	return (l1 + 0.05) / (l2 + 0.05)
+}
+
+// TextColorFor returns the color with the best contrast against a background,
+// chosen from the candidates, or from black and white if there are none
+func TextColorFor(bg color.NRGBA, candidates ...color.NRGBA) color.NRGBA {
+	if len(candidates) == 0 {
+		candidates = []color.NRGBA{{0, 0, 0, 255}, {255, 255, 255, 255}}
+	}
+	best, bestratio := candidates[0], 0.0
+	for _, c := range candidates {
+		if r := Contrast(bg, c); r > bestratio {
+			best, bestratio = c, r
+		}
+	}
+	return best
+}
+
+// OKLab returns the OKLab lightness (0-1), and a, b components of a color
+func OKLab(c color.NRGBA) (float64, float64, float64) {
+	r, g, b := linear(float64(c.R)/255), linear(float64(c.G)/255), linear(float64(c.B)/255)
+	l := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*b)
+	m := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*b)
+	s := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*b)
+	return 0.2104542553*l + 0.7936177850*m - 0.0040720468*s,
+		1.9779984951*l - 2.4285922050*m + 0.4505937099*s,
+		0.0259040371*l + 0.7827717662*m - 0.8086757660*s
+}
+
+// OKLCH returns the OKLCH lightness (0-1), chroma and hue (degrees) of a color
+func OKLCH(c color.NRGBA) (float64, float64, float64) {
+	l, a, b := OKLab(c)
+	h := math.Atan2(b, a) * 180 / math.Pi
+	if h < 0 {
+		h += 360
+	}
+	return l, math.Hypot(a, b), h
+}
+
+// FromOKLCH returns the color for OKLCH lightness (0-1), chroma, hue (degrees) and alpha.
+// Colors outside the sRGB gamut keep their lightness and hue, and have their chroma reduced.
+func FromOKLCH(l, ch, h float64, alpha uint8) color.NRGBA {
+	sin, cos := math.Sincos(h * math.Pi / 180)
+	r, g, b := oklab2rgb(l, ch*cos, ch*sin)
+	if !ingamut(r, g, b) {
+		lo, hi := 0.0, ch
+		for i := 0; i < 20; i++ {
+			mid := (lo + hi) / 2
+			if ingamut(oklab2rgb(l, mid*cos, mid*sin)) {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		r, g, b = oklab2rgb(l, lo*cos, lo*sin)
+	}
+	c := nrgba(r, g, b, 1)
+	c.A = alpha
+	return c
+}
+
+// ingamut reports whether sRGB values are displayable
+func ingamut(r, g, b float64) bool {
+	const e = 1e-4
+	return r >= -e && r <= 1+e && g >= -e && g <= 1+e && b >= -e && b <= 1+e
+}
+
+// rgb2hsl returns the hue (degrees), saturation and lightness (0-1) of a color
+func rgb2hsl(c color.NRGBA) (float64, float64, float64) {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	max, min := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	l := (max + min) / 2
+	d := max - min
+	if d == 0 {
+		return 0, 0, l
+	}
+	s := d / (1 - math.Abs(2*l-1))
+	var h float64
+	switch max {
+	case r:
+		h = math.Mod((g-b)/d+6, 6)
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	return h * 60, s, l
+}
+
+// lerp linearly interpolates between a and b
+func lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
+
+// clamp limits v to the range [min, max]
+func clamp(v, min, max float64) float64 {
+	return math.Max(min, math.Min(max, v))
+}
@@ -4,7 +4,6 @@ import (
 	"image/color"
 	"math"
 	"strconv"
-	"strings"
 )
 
 // colornames maps SVG color names to RGB triples.
@@ -128,6 +127,7 @@ var colornames = map[string]color.NRGBA{
 	"plum":                 {221, 160, 221, 255},
 	"powderblue":           {176, 224, 230, 255},
 	"purple":               {128, 0, 128, 255},
+	"rebeccapurple":        {102, 51, 153, 255},
 	"red":                  {255, 0, 0, 255},
 	"rosybrown":            {188, 143, 143, 255},
 	"royalblue":            {65, 105, 225, 255},
@@ -158,113 +158,24 @@ var colornames = map[string]color.NRGBA{
 	"yellowgreen":          {154, 205, 50, 255},
 }
 
-// cc converts a color string to number
-func cc(s string) uint8 {
-	v, err := strconv.Atoi(s)
-	if err != nil {
-		return 0
-	}
-	return uint8(v)
-}
-
-// hc converts a hex color string to number
-func hc(s string) uint8 {
-	v, err := strconv.ParseInt(s, 16, 32)
-	if err != nil {
-		return 0
-	}
-	return uint8(v)
-}
-
-// ColorLookup returns a color.RGBA corresponding to the named color or
+// ColorLookup returns a color.NRGBA corresponding to the named color or
 // "rgb(r)", "rgb(r,b)", "rgb(r,g,b), "rgb(r,g,b,a)",
-// "#rr",     "#rrgg",   "#rrggbb",   "#rrggbbaa" string.
-// "hsv(hue,sat,value)"
+// "#rr", "#rrgg", "#rgb", "#rrggbb", "#rrggbbaa" string,
+// "hsv(hue,sat,value)", or any other form accepted by ParseColor.
+// As before ParseColor, "#rrgg" has red and green components; ParseColor reads it as CSS "#rgba".
 // On error, return black.
 func ColorLookup(s string) color.NRGBA {
 	c, ok := colornames[s]
 	if ok {
 		return c
 	}
-	black := color.NRGBA{0, 0, 0, 255}
-	ls := len(s)
-	// rgb(...)
-	if strings.HasPrefix(s, "rgb(") && strings.HasSuffix(s, ")") && ls > 5 {
-		c.R, c.G, c.B, c.A = 0, 0, 0, 255
-		v := strings.Split(s[4:ls-1], ",")
-		switch len(v) {
-		case 1:
-			c.R = cc(v[0])
-			return c
-		case 2:
-			c.R = cc(v[0])
-			c.G = cc(v[1])
-			return c
-		case 3:
-			c.R = cc(v[0])
-			c.G = cc(v[1])
-			c.B = cc(v[2])
-			return c
-		case 4:
-			c.R = cc(v[0])
-			c.G = cc(v[1])
-			c.B = cc(v[2])
-			c.A = cc(v[3])
-			return c
-		default:
-			return black
+	if len(s) == 5 && s[0] == '#' {
+		if v, err := strconv.ParseUint(s[1:], 16, 16); err == nil {
+			return color.NRGBA{uint8(v >> 8), uint8(v), 0, 255}
 		}
 	}
-	// hsv(h,s,v) or hsv(h,s,v,a); h=0-360, s, v=0-100, a=0-100
-	if strings.HasPrefix(s, "hsv(") && strings.HasSuffix(s, ")") && ls > 5 {
-		v := strings.Split(s[4:ls-1], ",")
-		switch len(v) {
-		case 3:
-			hue, _ := strconv.ParseFloat(v[0], 64)
-			sat, _ := strconv.ParseFloat(v[1], 64)
-			value, _ := strconv.ParseFloat(v[2], 64)
-			c.R, c.G, c.B = hsv2rgb(hue, sat, value)
-			c.A = 255
-			return c
-		case 4:
-			hue, _ := strconv.ParseFloat(v[0], 64)
-			sat, _ := strconv.ParseFloat(v[1], 64)
-			value, _ := strconv.ParseFloat(v[2], 64)
-			a := cc(v[3])
-			if a > 100 {
-				a = 100
-			}
-			c.R, c.G, c.B = hsv2rgb(hue, sat, value)
-			c.A = uint8((float64(a) / 100.0) * 255.0)
-			return c
-		default:
-			return black
-		}
-	}
-	// #rrggbb
-	if strings.HasPrefix(s, "#") && (ls >= 3) {
-		c.R, c.G, c.B, c.A = 0, 0, 0, 255
-		switch ls {
-		case 3:
-			c.R = hc(s[1:3])
-		case 5:
-			c.R = hc(s[1:3])
-			c.G = hc(s[3:5])
-		case 7:
-			c.R = hc(s[1:3])
-			c.G = hc(s[3:5])
-			c.B = hc(s[5:7])
-		case 9:
-			c.R = hc(s[1:3])
-			c.G = hc(s[3:5])
-			c.B = hc(s[5:7])
-			c.A = hc(s[7:9])
-		default:
-			return black
-		}
-		return c
-	}
-	return black
+	c, _ = ParseColor(s)
+	return c
 }
 
 // hsv2rgb converts hsv(h (0-360), s (0-100), v (0-100)) to rgb
@@ -0,0 +1,244 @@
+package giocanvas
+
+import (
+	"image/color"
+	"math"
+	"strconv"
+)
+
+// Color schemes for scales. Viridis and Magma are from matplotlib,
+// Blues, RdBu and the categorical sets are from ColorBrewer (https://colorbrewer2.org).
+var (
+	// sequential
+	Viridis = hexcolors("#440154", "#482878", "#3e4989", "#31688e", "#26828e", "#1f9e89", "#35b779", "#6ece58", "#b5de2b", "#fde725")
+	Magma   = hexcolors("#000004", "#180f3d", "#440f76", "#721f81", "#9e2f7f", "#cd4071", "#f1605d", "#fd9668", "#feca8d", "#fcfdbf")
+	Blues   = hexcolors("#f7fbff", "#deebf7", "#c6dbef", "#9ecae1", "#6baed6", "#4292c6", "#2171b5", "#08519c", "#08306b")
+	// diverging
+	RdBu = hexcolors("#67001f", "#b2182b", "#d6604d", "#f4a582", "#fddbc7", "#f7f7f7", "#d1e5f0", "#92c5de", "#4393c3", "#2166ac", "#053061")
+	// categorical
+	Tableau10 = hexcolors("#4e79a7", "#f28e2c", "#e15759", "#76b7b2", "#59a14f", "#edc949", "#af7aa1", "#ff9da7", "#9c755f", "#bab0ab")
+	Set1      = hexcolors("#e41a1c", "#377eb8", "#4daf4a", "#984ea3", "#ff7f00", "#ffff33", "#a65628", "#f781bf", "#999999")
+	Set2      = hexcolors("#66c2a5", "#fc8d62", "#8da0cb", "#e78ac3", "#a6d854", "#ffd92f", "#e5c494", "#b3b3b3")
+	Dark2     = hexcolors("#1b9e77", "#d95f02", "#7570b3", "#e7298a", "#66a61e", "#e6ab02", "#a6761d", "#666666")
+	Paired    = hexcolors("#a6cee3", "#1f78b4", "#b2df8a", "#33a02c", "#fb9a99", "#e31a1c", "#fdbf6f", "#ff7f00", "#cab2d6", "#6a3d9a", "#ffff99", "#b15928")
+	Pastel1   = hexcolors("#fbb4ae", "#b3cde3", "#ccebc5", "#decbe4", "#fed9a6", "#ffffcc", "#e5d8bd", "#fddaec", "#f2f2f2")
+)
+
+// hexcolors makes a list of colors from hex strings
+func hexcolors(s ...string) []color.NRGBA {
+	colors := make([]color.NRGBA, len(s))
+	for i, h := range s {
+		colors[i] = ColorLookup(h)
+	}
+	return colors
+}
+
+// ScaleMode determines how a ColorScale maps values to colors
+type ScaleMode int
+
+const (
+	Continuous  ScaleMode = iota // smooth interpolation between the colors
+	Quantized                    // the domain divided into equal classes
+	Thresholded                  // classes divided at threshold values
+	Categorical                  // each integer value is a separate color
+)
+
+// ColorScale maps values in a domain to colors
+type ColorScale struct {
+	Colors     []color.NRGBA // color stops
+	Offsets    []float64     // positions of the color stops (0-1, ascending); nil spaces them evenly over the domain
+	Domain     []float64     // min and max, or min, mid and max for diverging scales
+	Mode       ScaleMode     // how values are mapped
+	Classes    int           // number of classes for Quantized scales
+	Thresholds []float64     // ascending class boundaries for Thresholded scales
+	Labels     []string      // category names for the legend of Categorical scales
+	NaN        color.NRGBA   // color for missing (NaN) values; transparent by default
+}
+
+// NewColorScale makes a continuous scale over a domain of (min, max),
+// or (min, mid, max) for diverging scales. The default domain is (0, 1).
+func NewColorScale(colors []color.NRGBA, domain ...float64) *ColorScale {
+	if len(domain) < 2 {
+		domain = []float64{0, 1}
+	}
+	return &ColorScale{Colors: colors, Domain: domain, Mode: Continuous}
+}
+
+// NewCategoricalScale makes a scale mapping integer values to colors, with optional legend labels
+func NewCategoricalScale(colors []color.NRGBA, labels ...string) *ColorScale {
+	return &ColorScale{Colors: colors, Domain: []float64{0, float64(len(colors) - 1)}, Mode: Categorical, Labels: labels}
+}
+
+// Stops places the color stops at offsets (0-1, ascending), one for each color;
+// with a diverging domain, 0.5 is the midpoint
+func (s *ColorScale) Stops(offsets ...float64) *ColorScale {
+	s.Offsets = offsets
+	return s
+}
+
+// Quantize divides the scale into n equal classes
+func (s *ColorScale) Quantize(n int) *ColorScale {
+	s.Mode = Quantized
+	s.Classes = n
+	return s
+}
+
+// Threshold divides the scale into classes at the threshold values
+func (s *ColorScale) Threshold(t ...float64) *ColorScale {
+	s.Mode = Thresholded
+	s.Thresholds = t
+	return s
+}
+
+// Color returns the color for a value, or the NaN color for a missing (NaN) value
+func (s *ColorScale) Color(v float64) color.NRGBA {
+	n := len(s.Colors)
+	if n == 0 {
+		return color.NRGBA{}
+	}
+	if math.IsNaN(v) {
+		return s.NaN
+	}
+	switch s.Mode {
+	case Categorical:
+		i := int(math.Floor(v)) % n
+		if i < 0 {
+			i += n
+		}
+		return s.Colors[i]
+	case Thresholded:
+		return s.class(s.threshold(v), len(s.Thresholds)+1)
+	case Quantized:
+		k := s.classes()
+		return s.class(int(math.Min(s.normalize(v)*float64(k), float64(k-1))), k)
+	}
+	return s.interpolate(s.normalize(v))
+}
+
+// interpolate returns the color at t (0-1) along the color stops
+func (s *ColorScale) interpolate(t float64) color.NRGBA {
+	return InterpolateAt(s.Colors, s.Offsets, t)
+}
+
+// classes returns the number of quantize classes
+func (s *ColorScale) classes() int {
+	if s.Classes > 0 {
+		return s.Classes
+	}
+	return len(s.Colors)
+}
+
+// class returns the color of class i of k; the colors are used directly if there is one per class
+func (s *ColorScale) class(i, k int) color.NRGBA {
+	if len(s.Colors) == k {
+		return s.Colors[i]
+	}
+	if k < 2 {
+		return s.interpolate(0.5)
+	}
+	return s.interpolate(float64(i) / float64(k-1))
+}
+
+// threshold returns the class of a value: the number of thresholds at or below it
+func (s *ColorScale) threshold(v float64) int {
+	i := 0
+	for _, t := range s.Thresholds {
+		if v >= t {
+			i++
+		}
+	}
+	return i
+}
+
+// normalize maps a value in the domain to 0-1.
+// For diverging scales, the midpoint maps to 0.5.
+func (s *ColorScale) normalize(v float64) float64 {
+	d := s.Domain
+	var t float64
+	switch {
+	case len(d) >= 3 && v < d[1]:
+		t = 0.5 * unitmap(v, d[0], d[1])
+	case len(d) >= 3:
+		t = 0.5 + 0.5*unitmap(v, d[1], d[len(d)-1])
+	default:
+		t = unitmap(v, d[0], d[len(d)-1])
+	}
+	return clamp(t, 0, 1)
+}
+
+// unitmap maps v from (low, high) to (0, 1)
+func unitmap(v, low, high float64) float64 {
+	if high == low {
+		return 0
+	}
+	return (v - low) / (high - low)
+}
+
+// value maps 0-1 back to the domain
+func (s *ColorScale) value(t float64) float64 {
+	d := s.Domain
+	switch {
+	case len(d) >= 3 && t < 0.5:
+		return d[0] + (d[1]-d[0])*t*2
+	case len(d) >= 3:
+		return d[1] + (d[len(d)-1]-d[1])*(t-0.5)*2
+	}
+	return d[0] + (d[len(d)-1]-d[0])*t
+}
+
+// Legend draws the scale on a canvas as a horizontal bar with upper left corner at (x, y), size (w, h).
+// If size > 0, labels are placed below the bar: category names, thresholds,
+// class boundaries, or for continuous scales, the number of evenly spaced values in ticks.
+func (s *ColorScale) Legend(c *Canvas, x, y, w, h float32, ticks int, size float32, labelcolor color.NRGBA) {
+	ly := y - h - size*1.5
+	label := func(lx float32, v float64) {
+		if size > 0 {
+			c.TextMid(lx, ly, size, strconv.FormatFloat(v, 'g', 4, 64), labelcolor)
+		}
+	}
+	switch s.Mode {
+	case Categorical:
+		n := len(s.Colors)
+		bw := w / float32(n)
+		for i, fill := range s.Colors {
+			bx := x + float32(i)*bw
+			c.CornerRect(bx, y, bw, h, fill)
+			if size > 0 {
+				name := strconv.Itoa(i)
+				if i < len(s.Labels) {
+					name = s.Labels[i]
+				}
+				c.TextMid(bx+bw/2, ly, size, name, labelcolor)
+			}
+		}
+	case Thresholded:
+		k := len(s.Thresholds) + 1
+		bw := w / float32(k)
+		for i := 0; i < k; i++ {
+			c.CornerRect(x+float32(i)*bw, y, bw, h, s.class(i, k))
+		}
+		for i, t := range s.Thresholds {
+			label(x+float32(i+1)*bw, t)
+		}
+	case Quantized:
+		k := s.classes()
+		bw := w / float32(k)
+		for i := 0; i <= k; i++ {
+			if i < k {
+				c.CornerRect(x+float32(i)*bw, y, bw, h, s.class(i, k))
+			}
+			label(x+float32(i)*bw, s.value(float64(i)/float64(k)))
+		}
+	default:
+		const slices = 100
+		bw := w / slices
+		for i := 0; i < slices; i++ {
+			t := (float64(i) + 0.5) / slices
+			c.CornerRect(x+float32(i)*bw, y, bw*1.05, h, s.interpolate(t))
+		}
+		for i := 0; i < ticks && ticks > 1; i++ {
+			t := float64(i) / float64(ticks-1)
+			label(x+w*float32(t), s.value(t))
+		}
+	}
+}
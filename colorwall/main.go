@@ -41,15 +41,25 @@ func colorwall(width, height float32) {
 		case app.FrameEvent:
 			canvas := giocanvas.NewCanvas(width, height, app.FrameEvent{})
 			canvas.Background(bgcolor)
+			// collect the squares of each color, and draw them together
+			var names []string
+			xs, ys := map[string][]float32{}, map[string][]float32{}
 			y = top
 			for i := 0; i < nr; i++ {
 				x = left
 				for j := 0; j < nc; j++ {
-					canvas.Square(x, y, yincr-0.1, giocanvas.ColorLookup(layout[i][j]))
+					name := layout[i][j]
+					if _, ok := xs[name]; !ok {
+						names = append(names, name)
+					}
+					xs[name], ys[name] = append(xs[name], x), append(ys[name], y)
 					x += xincr
 				}
 				y -= yincr
 			}
+			for _, name := range names {
+				canvas.Squares(xs[name], ys[name], []float32{yincr - 0.1}, giocanvas.ColorLookup(name))
+			}
 			e.Frame(canvas.Context.Ops)
 
 		case app.DestroyEvent:
@@ -3,9 +3,9 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"image/color"
 	"io"
-	"math/rand"
 	"os"
 
 	"gioui.org/app"
@@ -13,15 +13,15 @@ import (
 	"github.com/ajstarks/giocanvas"
 )
 
-func rn(n int) float32 {
-	return float32(rand.Intn(n))
+func rn(r *giocanvas.Rand, n int) float32 {
+	return float32(r.Intn(n))
 }
 
-func rn8(n int) uint8 {
-	return uint8(rand.Intn(n))
+func rn8(r *giocanvas.Rand, n int) uint8 {
+	return uint8(r.Intn(n))
 }
 
-func confetti(w *app.Window, nshapes, maxsize int) error {
+func confetti(w *app.Window, nshapes, maxsize int, seed int64) error {
 	for {
 		e := w.Event()
 		switch e := e.(type) {
@@ -30,10 +30,11 @@ func confetti(w *app.Window, nshapes, maxsize int) error {
 		case app.FrameEvent:
 			canvas := giocanvas.NewCanvas(float32(e.Size.X), float32(e.Size.Y), app.FrameEvent{})
 			canvas.CenterRect(50, 50, 100, 100, color.NRGBA{0, 0, 0, 255})
+			r := giocanvas.NewRand(seed)
 			for i := 0; i < nshapes; i++ {
-				color := color.NRGBA{rn8(255), rn8(255), rn8(255), rn8(255)}
-				x, y := rn(100), rn(100)
-				w, h := rn(maxsize), rn(maxsize)
+				color := color.NRGBA{rn8(r, 255), rn8(r, 255), rn8(r, 255), rn8(r, 255)}
+				x, y := rn(r, 100), rn(r, 100)
+				w, h := rn(r, maxsize), rn(r, maxsize)
 				if i%2 == 0 {
 					canvas.Ellipse(x, y, w, h, color)
 				} else {
@@ -48,11 +49,15 @@ func confetti(w *app.Window, nshapes, maxsize int) error {
 
 func main() {
 	var cw, ch, nshapes, maxsize int
+	var seed int64
 	flag.IntVar(&cw, "width", 1000, "canvas width")
 	flag.IntVar(&ch, "height", 1000, "canvas height")
 	flag.IntVar(&nshapes, "n", 500, "number of shapes")
 	flag.IntVar(&maxsize, "size", 10, "max size")
+	flag.Int64Var(&seed, "seed", 0, "random seed (0: use the time)")
 	flag.Parse()
+	seed = giocanvas.NewRand(seed).Seed()
+	fmt.Fprintf(os.Stderr, "seed: %d\n", seed)
 
 	width := float32(cw)
 	height := float32(ch)
@@ -60,7 +65,7 @@ func main() {
 	go func() {
 		w := &app.Window{}
 		w.Option(app.Title("confetti"), app.Size(unit.Dp(width), unit.Dp(height)))
-		if err := confetti(w, nshapes, maxsize); err != nil {
+		if err := confetti(w, nshapes, maxsize, seed); err != nil {
 			io.WriteString(os.Stderr, "Cannot create the window\n")
 			os.Exit(1)
 		}
@@ -5,7 +5,6 @@ import (
 	"flag"
 	"fmt"
 	"image/color"
-	"math/rand"
 	"os"
 	"strconv"
 	"strings"
@@ -18,19 +17,23 @@ import (
 	"gioui.org/op"
 	"gioui.org/unit"
 	"github.com/ajstarks/giocanvas"
+	"github.com/ajstarks/giocanvas/palette"
 )
 
-var colorpalette rgbpalette
+var colorpalette palette.Map
+var rnd *giocanvas.Rand
+var capture giocanvas.Capture
 
 // config holds configuration parameters
 type config struct {
 	tiles, maxlw, h1, h2 float64
 	bgcolor, color       string
+	seed                 int64
 }
 
 // random returns a random number between a range
 func random(min, max float64) float64 {
-	return vmap(rand.Float64(), 0, 1, min, max)
+	return vmap(rnd.Float64(), 0, 1, min, max)
 }
 
 // vmap maps one interval to another
@@ -38,19 +41,14 @@ func vmap(value, low1, high1, low2, high2 float64) float64 {
 	return low2 + (high2-low2)*(value-low1)/(high1-low1)
 }
 
-// hsv makes a color given hue, saturation, value
-func hsv(hue, sat, value int) color.NRGBA {
-	return giocanvas.ColorLookup(fmt.Sprintf("hsv(%d,%d,%d)", hue, sat, value))
-}
-
 // csquare makes squares, with possibly random colors, centered at (x,y)
 func csquare(canvas *giocanvas.Canvas, x, y, size, maxlw, h1, h2 float64, linecolor string) {
 	var color color.NRGBA
 
 	if c, ok := colorpalette[linecolor]; ok { // use a palette
-		color = c[rand.Intn(len(c)-1)]
+		color = giocanvas.Choice(rnd, c)
 	} else if h1 > -1 && h2 > -1 { // hue range set
-		color = hsv(int(random(h1, h2)), 100, 100)
+		color = giocanvas.HSV(random(h1, h2), 100, 100)
 	} else {
 		color = giocanvas.ColorLookup(linecolor)
 	}
@@ -63,15 +61,14 @@ func csquare(canvas *giocanvas.Canvas, x, y, size, maxlw, h1, h2 float64, lineco
 	// make the boundaries
 	lw := float32(random(0.1, maxlw))
 	ll := float32(size)
-	canvas.HLine(tlx, tly, ll, lw, color)
-	canvas.HLine(blx, bly, ll, lw, color)
-	canvas.VLine(blx, bly, ll, lw, color)
-	canvas.VLine(brx, bry, ll, lw, color)
+	canvas.Lines( // top, bottom, left and right sides
+		[]float32{tlx, blx, blx, brx},
+		[]float32{tly, bly, bly, bry},
+		[]float32{tlx + ll, blx + ll, blx, brx},
+		[]float32{tly, bly, bly + ll, bry + ll},
+		lw, color)
 	// make the corners
-	canvas.Square(tlx, tly, lw, color)
-	canvas.Square(blx, bly, lw, color)
-	canvas.Square(brx, bry, lw, color)
-	canvas.Square(trx, try, lw, color)
+	canvas.Squares([]float32{tlx, blx, brx, trx}, []float32{tly, bly, bry, try}, []float32{lw}, color)
 }
 
 // parseHues parses a color string: if the string is of the form "h1:h2",
@@ -102,16 +99,9 @@ func tiles(canvas *giocanvas.Canvas, x, y, minsize, maxsize, maxlw, h1, h2 float
 	}
 }
 
+// randpalette returns the name of a random palette
 func randpalette() string {
-	n := rand.Intn(len(colorpalette) - 1)
-	i := 0
-	for p := range colorpalette {
-		if i == n {
-			return p
-		}
-		i++
-	}
-	return "2-bit-grayscale"
+	return giocanvas.Choice(rnd, colorpalette.Names())
 }
 
 var pressed bool
@@ -135,7 +125,9 @@ func kbpointer(q input.Source, context *op.Ops) {
 		}
 		switch e := e.(type) {
 		case key.Event: // keyboard events
-
+			if capture.Key(e) {
+				continue
+			}
 			switch e.State {
 			case key.Press:
 				switch e.Name {
@@ -150,7 +142,7 @@ func kbpointer(q input.Source, context *op.Ops) {
 				case "P":
 					pencolor = randpalette()
 				case key.NameEscape, "Q":
-					os.Exit(0)
+					quit()
 				}
 			}
 
@@ -172,6 +164,15 @@ func kbpointer(q input.Source, context *op.Ops) {
 	event.Op(context, &pressed)
 }
 
+// quit finishes a recording, and exits
+func quit() {
+	if err := capture.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
 // desordres makes tiles of random conentric squares
 func desordres(w *app.Window, cfg config) error {
 	bg := giocanvas.ColorLookup(cfg.bgcolor)
@@ -184,11 +185,15 @@ func desordres(w *app.Window, cfg config) error {
 		e := w.Event()
 		switch e := e.(type) {
 		case app.DestroyEvent:
+			if err := capture.Close(); err != nil {
+				return err
+			}
 			return e.Err
 		case app.FrameEvent:
 			canvas := giocanvas.NewCanvas(float32(e.Size.X), float32(e.Size.Y), app.FrameEvent{})
 
 			canvas.Background(bg)
+			rnd = giocanvas.NewRand(cfg.seed) // the same seed makes the same tiles
 			if tilesize < mintile {
 				tilesize = mintile
 			}
@@ -204,6 +209,9 @@ func desordres(w *app.Window, cfg config) error {
 				}
 			}
 			kbpointer(e.Source, canvas.Context.Ops)
+			if err := capture.Frame(canvas); err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+			}
 			e.Frame(canvas.Context.Ops)
 		}
 	}
@@ -218,10 +226,16 @@ func usage() {
 	fmt.Fprintf(os.Stderr, "-height     1000        canvas height\n")
 	fmt.Fprintf(os.Stderr, "-tiles      10          number of tiles/row\n")
 	fmt.Fprintf(os.Stderr, "-maxlw      1           maximim line thickness\n")
+	fmt.Fprintf(os.Stderr, "-seed       0           random seed (0: use the time)\n")
+	fmt.Fprintf(os.Stderr, "-snapshot   \"\"          save the first frame to a PNG file\n")
+	fmt.Fprintf(os.Stderr, "-record     \"\"          record to a .gif, .png or .apng file, or numbered PNG files (frame-%%03d.png)\n")
+	fmt.Fprintf(os.Stderr, "-fps        30          frame rate of recordings\n")
+	fmt.Fprintf(os.Stderr, "-frames     0           number of frames to record (0: until stopped)\n")
 	fmt.Fprintf(os.Stderr, "-bgcolor    white       background color\n")
-	fmt.Fprintf(os.Stderr, "-p          \"\"          palette file\n")
+	fmt.Fprintf(os.Stderr, "-p          \"\"          palette file (.pal, .gpl, .hex, .ase, .json)\n")
 	fmt.Fprintf(os.Stderr, "-color      gray        color name, h1:h2, or palette:\n\n")
-	for p, k := range colorpalette {
+	for _, p := range colorpalette.Names() {
+		k := colorpalette[p]
 		fmt.Fprintf(os.Stderr, "%-20s\t", p)
 		end := len(k) - 1
 		for i := 0; i < end; i++ {
@@ -233,24 +247,10 @@ func usage() {
 
 }
 
-// convert the built-in palette from string based to rgb
-func convertpalette() {
-	colorpalette = make(rgbpalette)
-	for name, value := range palette {
-		colors := make([]color.NRGBA, len(value))
-		for i, c := range value {
-			x, _ := strconv.ParseUint(c[1:], 16, 32)
-			r, g, b := rgb(uint32(x))
-			colors[i] = color.NRGBA{R: r, G: g, B: b, A: 0xff}
-		}
-		colorpalette[name] = colors
-	}
-}
-
 // load a palette from a file
 func loadpalette(pfile string) {
 	var err error
-	colorpalette, err = LoadRGBPalette(pfile)
+	colorpalette, err = palette.Load(pfile)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
@@ -271,6 +271,8 @@ func main() {
 	flag.Float64Var(&cfg.maxlw, "maxlw", 1, "maximum line thickness")
 	flag.StringVar(&cfg.bgcolor, "bgcolor", "white", "background color")
 	flag.StringVar(&cfg.color, "color", "gray", "pen color; named color, or h1:h2 for a random hue range hsv(h1:h2, 100, 100)")
+	flag.Int64Var(&cfg.seed, "seed", 0, "random seed (0: use the time)")
+	capture.Flags(flag.CommandLine)
 	flag.Parse()
 
 	width, height := float32(cw), float32(ch)
@@ -281,11 +283,13 @@ func main() {
 	if len(pfile) > 0 {
 		loadpalette(pfile)
 	} else {
-		convertpalette()
+		colorpalette = palette.Builtin()
 	}
 	if showhelp {
 		usage()
 	}
+	cfg.seed = giocanvas.NewRand(cfg.seed).Seed()
+	fmt.Fprintf(os.Stderr, "seed: %d\n", cfg.seed)
 
 	go func() {
 		w := &app.Window{}
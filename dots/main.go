@@ -3,6 +3,7 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"io"
 	"os"
 	"strings"
@@ -15,6 +16,7 @@ import (
 	"gioui.org/op"
 	"gioui.org/unit"
 	"github.com/ajstarks/giocanvas"
+	"github.com/ajstarks/giocanvas/palette"
 )
 
 type coord struct {
@@ -24,20 +26,39 @@ type coord struct {
 
 func main() {
 	var cw, ch, nc int
-	var bgcolor, palette string
+	var bgcolor, colors string
+	var scatter float64
+	var seed int64
 	flag.IntVar(&cw, "width", 1000, "canvas width")
 	flag.IntVar(&ch, "height", 1000, "canvas height")
 	flag.IntVar(&nc, "nc", 1000, "number of dots")
 	flag.StringVar(&bgcolor, "bgcolor", "black", "background color")
-	flag.StringVar(&palette, "palette", "#aaaaaaaa #aa0000aa #00aa00aa #0000aaaa #ffd821aa #234ad5aa #ffad5e00 #000000aa", "color palette (space separated list of colors)")
+	flag.StringVar(&colors, "palette", "#aaaaaaaa #aa0000aa #00aa00aa #0000aaaa #ffd821aa #234ad5aa #ffad5e00 #000000aa", "color palette (built-in palette name, palette file, or space separated list of colors)")
+	flag.Float64Var(&scatter, "scatter", 0, "start with the dots scattered around the center (standard deviation, percent)")
+	flag.Int64Var(&seed, "seed", 0, "random seed for the scattered dots (0: use the time)")
 	flag.Parse()
+	palette, err := parsePalette(colors)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	coordinates := make([]coord, nc)
+	coordinates[0].X, coordinates[0].Y = 50, 50
+	if scatter > 0 {
+		r := giocanvas.NewRand(seed)
+		fmt.Fprintf(os.Stderr, "seed: %d\n", r.Seed())
+		for i := range coordinates {
+			coordinates[i].X, coordinates[i].Y = float32(r.Normal(50, scatter)), float32(r.Normal(50, scatter))
+		}
+	}
 
 	// kick off the application
 
 	go func() {
 		w := &app.Window{}
 		w.Option(app.Title("dots"), app.Size(unit.Dp(cw), unit.Dp(ch)))
-		if err := dots(w, nc, bgcolor, palette); err != nil {
+		if err := dots(w, coordinates, bgcolor, palette); err != nil {
 			io.WriteString(os.Stderr, "Cannot create the window\n")
 			os.Exit(1)
 		}
@@ -103,23 +124,31 @@ func kbpointer(q input.Source, context *op.Ops, width, height float32, coords []
 	event.Op(context, &pressed)
 }
 
-func parseColors(s string) []string {
-	c := strings.Fields(s)
-	l := len(c)
-	if l < 2 {
-		return []string{"red", "blue"}
+// parsePalette makes the dot colors from a built-in palette name, a palette file
+// (using its first palette), or a space separated list of colors
+func parsePalette(s string) (palette.Palette, error) {
+	if p, ok := palette.Builtin()[s]; ok {
+		return p, nil
 	}
-	for i := 0; i < l; i++ {
-		c[i] = strings.TrimSpace(c[i])
+	if _, err := os.Stat(s); err == nil {
+		m, err := palette.Load(s)
+		if err != nil {
+			return nil, err
+		}
+		names := m.Names()
+		if len(names) == 0 {
+			return nil, fmt.Errorf("%s: no palettes", s)
+		}
+		return m[names[0]], nil
+	}
+	c := strings.Fields(s)
+	if len(c) < 2 {
+		c = []string{"red", "blue"}
 	}
-	return c
+	return palette.Parse(c)
 }
 
-func dots(w *app.Window, nc int, bgcolor, colorlist string) error {
-	palette := parseColors(colorlist)
-	np := len(palette)
-	coordinates := make([]coord, nc)
-	coordinates[0].X, coordinates[0].Y = 50, 50
+func dots(w *app.Window, coordinates []coord, bgcolor string, colors palette.Palette) error {
 	bg := giocanvas.ColorLookup(bgcolor)
 	for {
 		ev := w.Event()
@@ -135,15 +164,18 @@ func dots(w *app.Window, nc int, bgcolor, colorlist string) error {
 			if dotsize > 5 {
 				dotsize = 5
 			}
-			cs := dotsize
-			ci := 0
 			canvas.Background(bg)
-			for i := 0; i < nc; i++ {
-				x1, y1 := coordinates[i].X, coordinates[i].Y
-				c := giocanvas.ColorLookup(palette[ci%np])
-				canvas.Circle(x1, y1, cs, c)
-				cs += 0.01
-				ci++
+			// the dots cycle through the colors, growing in size; draw them in order,
+			// batching each run of dots of the same color
+			var xs, ys, rs []float32
+			for i, p := range coordinates {
+				xs = append(xs, p.X)
+				ys = append(ys, p.Y)
+				rs = append(rs, dotsize+float32(i)*0.01)
+				if c := colors.Color(i); i == len(coordinates)-1 || colors.Color(i+1) != c {
+					canvas.Circles(xs, ys, rs, c)
+					xs, ys, rs = xs[:0], ys[:0], rs[:0]
+				}
 			}
 			kbpointer(e.Source, canvas.Context.Ops, w, h, coordinates)
 			e.Frame(canvas.Context.Ops)
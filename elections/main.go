@@ -180,7 +180,7 @@ func process(canvas *gc.Canvas, opts options, e election) {
 		apop := area(fpop)
 
 		// defaults
-		txcolor := "white"
+		txcolor := labelcolor(partyColors[d.party])
 		txsize := 1.2
 		font := "sans"
 		name := d.name
@@ -268,6 +268,12 @@ func circle(canvas *gc.Canvas, x, y, r float64, color string) {
 	canvas.Circle(cx, cy, cr/2, gc.ColorLookup(color))
 }
 
+// labelcolor returns the text color that best contrasts with a fill color
+func labelcolor(fill string) string {
+	c := gc.TextColorFor(gc.ColorLookup(fill))
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
 // ctext makes centered text
 func ctext(canvas *gc.Canvas, x, y, size float64, s string, fontname string, color string) {
 	canvas.Theme.Face = font.Typeface(fontmap[fontname])
@@ -288,33 +294,12 @@ func square(canvas *gc.Canvas, x, y, w float64, color string) {
 
 // hexagon makes a filled hexagon centered at (cx, cy), size is the subscribed circle radius r
 func hexagon(canvas *gc.Canvas, cx, cy, r float64, color string) {
-	// construct a polygon with points at these angles
-	angles := []float32{30, 90, 150, 210, 270, 330}
-	px := make([]float32, len(angles))
-	py := make([]float32, len(angles))
-	x, y, rad := float32(cx), float32(cy), float32(r)
-	for i, a := range angles {
-		px[i], py[i] = canvas.PolarDegrees(x, y, rad, a)
-	}
-	canvas.Polygon(px, py, gc.ColorLookup(color))
+	canvas.RegularPolygon(float32(cx), float32(cy), 6, float32(r), 0, gc.ColorLookup(color))
 }
 
 // polylines makes a outlined hexagon, centered at (cx, cy), size is the subscribed circle radius r
 func polylines(canvas *gc.Canvas, cx, cy, r, lw float64, color string) {
-	// construct a polygon with points at these angles
-	angles := []float32{30, 90, 150, 210, 270, 330} // square: []float64{45, 135, 225, 315}
-	px := make([]float32, len(angles))
-	py := make([]float32, len(angles))
-	x, y, rad := float32(cx), float32(cy), float32(r)
-	linewidth := float32(lw)
-	for i, a := range angles {
-		px[i], py[i] = canvas.PolarDegrees(x, y, rad, a)
-	}
-	lx := len(px) - 1
-	for i := 0; i < lx; i++ {
-		canvas.Line(px[i], py[i], px[i+1], py[i+1], linewidth, gc.ColorLookup(color))
-	}
-	canvas.Line(px[0], py[0], px[lx], py[lx], linewidth, gc.ColorLookup(color))
+	canvas.StrokedRegularPolygon(float32(cx), float32(cy), 6, float32(r), 0, float32(lw), gc.ColorLookup(color))
 }
 
 // legend makes the subtitle
@@ -335,6 +320,7 @@ func endPage(canvas *gc.Canvas) {
 
 var pressed bool
 var electionNumber int
+var capture gc.Capture
 
 func kbpointer(q input.Source, context *op.Ops, ns int) {
 	nev := 0
@@ -349,6 +335,9 @@ func kbpointer(q input.Source, context *op.Ops, ns int) {
 		switch e := e.(type) {
 
 		case key.Event:
+			if capture.Key(e) {
+				continue
+			}
 			switch e.State {
 			case key.Press:
 				switch e.Name {
@@ -392,7 +381,7 @@ func kbpointer(q input.Source, context *op.Ops, ns int) {
 				case key.NameLeftArrow, key.NamePageUp, key.NameUpArrow, "J":
 					electionNumber--
 				case key.NameEscape, "Q":
-					os.Exit(0)
+					quit()
 				}
 			}
 
@@ -451,6 +440,15 @@ func loadfonts(fonts []string) ([]font.FontFace, error) {
 	return collection, nil
 }
 
+// quit finishes a recording, and exits
+func quit() {
+	if err := capture.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
 func elect(title string, opts options, elections []election) {
 	fc, err := loadfonts([]string{"Go-Bold.ttf", "stateface.ttf"})
 	if err != nil {
@@ -466,7 +464,7 @@ func elect(title string, opts options, elections []election) {
 		e := w.Event()
 		switch e := e.(type) {
 		case app.DestroyEvent:
-			os.Exit(0)
+			quit()
 		case app.FrameEvent:
 			canvas := gc.NewCanvasFonts(float32(e.Size.X), float32(e.Size.Y), fc, app.FrameEvent{})
 			if electionNumber > ne {
@@ -477,6 +475,9 @@ func elect(title string, opts options, elections []election) {
 			}
 			process(canvas, opts, elections[electionNumber])
 			kbpointer(e.Source, canvas.Context.Ops, ne)
+			if err := capture.Frame(canvas); err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+			}
 			e.Frame(canvas.Context.Ops)
 		}
 	}
@@ -497,6 +498,7 @@ func main() {
 	flag.StringVar(&opts.bgcolor, "bgcolor", "black", "background color")
 	flag.StringVar(&opts.textcolor, "textcolor", "white", "text color")
 	flag.StringVar(&opts.shape, "shape", "c", "shape for states:\n\"c\": circle,\n\"h\": hexagon,\n\"s\": square\n\"l\": line\n\"g\": geographic\n\"p\": plain text")
+	capture.Flags(flag.CommandLine)
 	flag.Parse()
 
 	// Read in the data
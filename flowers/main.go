@@ -17,7 +17,7 @@ func petals(canvas *giocanvas.Canvas, x, y, w, h float32, fill color.NRGBA) {
 	for a = 0; a < math.Pi*2; a += math.Pi / 9 {
 		stack := canvas.Rotate(x, y, a)
 		canvas.Ellipse(x, y, w, h, fill)
-		giocanvas.EndTransform(stack)
+		canvas.EndTransform(stack)
 	}
 }
 
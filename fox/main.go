@@ -5,7 +5,6 @@ import (
 	"flag"
 	"fmt"
 	"image/color"
-	"math/rand"
 	"os"
 	"strconv"
 	"strings"
@@ -18,6 +17,7 @@ import (
 	"gioui.org/op"
 	"gioui.org/unit"
 	"github.com/ajstarks/giocanvas"
+	"github.com/ajstarks/giocanvas/palette"
 )
 
 const stepsize = 0.5
@@ -29,18 +29,21 @@ const defaultstep = 5.0
 const shadowshift = 0.5
 const rangefmt = "%v,%v,%v"
 
-var colorpalette rgbpalette
+var colorpalette palette.Map
+var rnd *giocanvas.Rand
+var capture giocanvas.Capture
 
 // config holds configuration parameters
 type config struct {
 	hue1, hue2                               float64
 	beginx, beginy, endx, endy, xstep, ystep float32
 	bgcolor, color                           string
+	seed                                     int64
 }
 
 // random returns a random number between a range
 func random(min, max float64) float64 {
-	return vmap(rand.Float64(), 0, 1, min, max)
+	return vmap(rnd.Float64(), 0, 1, min, max)
 }
 
 // vmap maps one interval to another
@@ -48,13 +51,9 @@ func vmap(value, low1, high1, low2, high2 float64) float64 {
 	return low2 + (high2-low2)*(value-low1)/(high1-low1)
 }
 
-// hsv makes a color given hue, saturation, value
-func hsv(hue, sat, value int) color.NRGBA {
-	return giocanvas.ColorLookup(fmt.Sprintf("hsv(%d,%d,%d)", hue, sat, value))
-}
-
+// randhsv makes a fully saturated color with a random hue between h1 and h2
 func randhsv(h1, h2 float64) color.NRGBA {
-	return giocanvas.ColorLookup(fmt.Sprintf("hsv(%d,100,100)", int(random(h1, h2))))
+	return giocanvas.HSV(random(h1, h2), 100, 100)
 }
 
 // parseHues parses a color string: if the string is of the form "h1:h2",
@@ -77,16 +76,9 @@ func parseHues(color string) (float64, float64) {
 	return h1, h2
 }
 
+// randpalette returns the name of a random palette
 func randpalette() string {
-	n := rand.Intn(len(colorpalette))
-	i := 0
-	for p := range colorpalette {
-		if i == n {
-			return p
-		}
-		i++
-	}
-	return "2-bit-grayscale"
+	return giocanvas.Choice(rnd, colorpalette.Names())
 }
 
 var pressed bool
@@ -107,7 +99,9 @@ func kbpointer(q input.Source, context *op.Ops) {
 
 		// keyboard events
 		case key.Event:
-
+			if capture.Key(e) {
+				continue
+			}
 			switch e.State {
 			case key.Press:
 				switch e.Name {
@@ -130,7 +124,7 @@ func kbpointer(q input.Source, context *op.Ops) {
 					gex, gey = maxbound, maxbound
 					gxstep, gystep = minbound, minbound
 				case key.NameEscape, "Q":
-					os.Exit(0)
+					quit()
 				}
 			}
 		case pointer.Event:
@@ -188,11 +182,20 @@ func triangle(canvas *giocanvas.Canvas, x, y, width, height float32, tcolor stri
 		fillcolor = randhsv(hue1, hue2)
 	}
 	if c, ok := colorpalette[tcolor]; ok { // use a palette
-		fillcolor = c[rand.Intn(len(c))]
+		fillcolor = giocanvas.Choice(rnd, c)
 	}
 	canvas.Polygon(xp, yp, fillcolor)
 }
 
+// quit finishes a recording, and exits
+func quit() {
+	if err := capture.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
 // fox makes...
 func fox(w *app.Window, width, height float32, cfg config) error {
 	bg := giocanvas.ColorLookup(cfg.bgcolor)
@@ -203,8 +206,11 @@ func fox(w *app.Window, width, height float32, cfg config) error {
 	var directions = []string{"u", "d", "l", "r", "nw", "ne", "sw", "se"}
 
 	for {
-		switch e := w.NextEvent().(type) {
+		switch e := w.Event().(type) {
 		case app.DestroyEvent:
+			if err := capture.Close(); err != nil {
+				return err
+			}
 			return e.Err
 		case app.FrameEvent:
 
@@ -238,16 +244,20 @@ func fox(w *app.Window, width, height float32, cfg config) error {
 
 			canvas := giocanvas.NewCanvas(float32(e.Size.X), float32(e.Size.Y), app.FrameEvent{})
 			canvas.Background(bg)
+			rnd = giocanvas.NewRand(cfg.seed) // the same seed makes the same triangles
 			for y := gby; y < gey; y += gystep {
 				for x := gbx; x < gex; x += gxstep {
 					w := float32(random(minstep, float64(gxstep)))
 					h := float32(random(minstep, float64(gystep)))
-					triangle(canvas, x, y, w, h, pencolor, cfg.hue1, cfg.hue2, directions[rand.Intn(len(directions))])
-					triangle(canvas, x+shadowshift, y-shadowshift, w, h, pencolor, cfg.hue1, cfg.hue2, directions[rand.Intn(4)])
+					triangle(canvas, x, y, w, h, pencolor, cfg.hue1, cfg.hue2, giocanvas.Choice(rnd, directions))
+					triangle(canvas, x+shadowshift, y-shadowshift, w, h, pencolor, cfg.hue1, cfg.hue2, giocanvas.Choice(rnd, directions[:4]))
 
 				}
 			}
 			kbpointer(e.Source, canvas.Context.Ops)
+			if err := capture.Frame(canvas); err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+			}
 			e.Frame(canvas.Context.Ops)
 		}
 	}
@@ -263,10 +273,16 @@ func usage() {
 	fmt.Fprintf(os.Stderr, "-height     1000        canvas height\n")
 	fmt.Fprintf(os.Stderr, "-w          "+defrange+"     percent begin,end,step for the width\n")
 	fmt.Fprintf(os.Stderr, "-h          "+defrange+"     percent begin,end,step for the height\n")
-	fmt.Fprintf(os.Stderr, "-p          \"\"          palette file\n")
+	fmt.Fprintf(os.Stderr, "-p          \"\"          palette file (.pal, .gpl, .hex, .ase, .json)\n")
 	fmt.Fprintf(os.Stderr, "-bgcolor    white       background color\n")
+	fmt.Fprintf(os.Stderr, "-seed       0           random seed (0: use the time)\n")
+	fmt.Fprintf(os.Stderr, "-snapshot   \"\"          save the first frame to a PNG file\n")
+	fmt.Fprintf(os.Stderr, "-record     \"\"          record to a .gif, .png or .apng file, or numbered PNG files (frame-%%03d.png)\n")
+	fmt.Fprintf(os.Stderr, "-fps        30          frame rate of recordings\n")
+	fmt.Fprintf(os.Stderr, "-frames     0           number of frames to record (0: until stopped)\n")
 	fmt.Fprintf(os.Stderr, "-color      gray        color name, h1:h2, or palette:\n\n")
-	for p, k := range colorpalette {
+	for _, p := range colorpalette.Names() {
+		k := colorpalette[p]
 		fmt.Fprintf(os.Stderr, "%-20s\t", p)
 		end := len(k) - 1
 		for i := 0; i < end; i++ {
@@ -297,24 +313,10 @@ func parserange(s string) (float32, float32, float32) {
 	return minbound, maxbound, defaultstep
 }
 
-// convert the built-in palette from string based to rgb
-func convertpalette() {
-	colorpalette = make(rgbpalette)
-	for name, value := range palette {
-		colors := make([]color.NRGBA, len(value))
-		for i, c := range value {
-			x, _ := strconv.ParseUint(c[1:], 16, 32)
-			r, g, b := rgb(uint32(x))
-			colors[i] = color.NRGBA{R: r, G: g, B: b, A: 0xff}
-		}
-		colorpalette[name] = colors
-	}
-}
-
 // load a palette from a file
 func loadpalette(pfile string) {
 	var err error
-	colorpalette, err = LoadRGBPalette(pfile)
+	colorpalette, err = palette.Load(pfile)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
@@ -335,23 +337,28 @@ func main() {
 	flag.StringVar(&pfile, "p", "", "palette file")
 	flag.StringVar(&cfg.bgcolor, "bgcolor", "white", "background color")
 	flag.StringVar(&cfg.color, "color", "gray", "pen color; named color, palette, or h1:h2 for a random hue range hsv(h1:h2, 100, 100)")
+	flag.Int64Var(&cfg.seed, "seed", 0, "random seed (0: use the time)")
+	capture.Flags(flag.CommandLine)
 	flag.Parse()
 
 	if len(pfile) > 0 {
 		loadpalette(pfile)
 	} else {
-		convertpalette()
+		colorpalette = palette.Builtin()
 	}
 	if showhelp {
 		usage()
 	}
+	cfg.seed = giocanvas.NewRand(cfg.seed).Seed()
+	fmt.Fprintf(os.Stderr, "seed: %d\n", cfg.seed)
 	cfg.beginx, cfg.endx, cfg.xstep = parserange(xconfig)
 	cfg.beginy, cfg.endy, cfg.ystep = parserange(yconfig)
 	cfg.hue1, cfg.hue2 = parseHues(cfg.color)
 	width, height := float32(cw), float32(ch)
 
 	go func() {
-		w := app.NewWindow(app.Title("fox"), app.Size(unit.Dp(width), unit.Dp(height)))
+		w := &app.Window{}
+		w.Option(app.Title("fox"), app.Size(unit.Dp(width), unit.Dp(height)))
 		if err := fox(w, width, height, cfg); err != nil {
 			fmt.Fprintf(os.Stderr, "Cannot create the window: %v\n", err)
 			os.Exit(1)
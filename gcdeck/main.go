@@ -2,10 +2,13 @@
 package main
 
 import (
+	"bytes"
+	"encoding/xml"
 	"flag"
 	"fmt"
 	"image"
 	"image/color"
+	"io"
 	"math"
 	"os"
 	"path"
@@ -27,6 +30,7 @@ import (
 	"gioui.org/io/key"
 	"gioui.org/io/pointer"
 	"gioui.org/op"
+	"gioui.org/text"
 	"gioui.org/unit"
 	"github.com/ajstarks/deck"
 	gc "github.com/ajstarks/giocanvas"
@@ -128,6 +132,19 @@ func fontlookup(s string) string {
 	return "sans"
 }
 
+// badcolors records the color errors already reported
+var badcolors = map[string]bool{}
+
+// colorlookup returns the color for a specification, reporting errors once
+func colorlookup(s string) color.NRGBA {
+	_, err := gc.ParseColor(s)
+	if err != nil && !badcolors[s] {
+		badcolors[s] = true
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+	}
+	return gc.ColorLookup(s)
+}
+
 // setop sets the opacity as a truncated fraction of 255
 func setop(v float64) uint8 {
 	if v > 0.0 {
@@ -142,7 +159,7 @@ func gradient(doc *gc.Canvas, w, h float64, gc1, gc2 string, gp float64) {
 
 // doline draws a line
 func doline(doc *gc.Canvas, xp1, yp1, xp2, yp2, sw float64, color string, opacity float64) {
-	c := gc.ColorLookup(color)
+	c := colorlookup(color)
 	c.A = setop(opacity)
 	switch {
 	case yp1 == yp2: // horizontal line
@@ -154,30 +171,75 @@ func doline(doc *gc.Canvas, xp1, yp1, xp2, yp2, sw float64, color string, opacit
 	}
 }
 
-// doarc draws an arc
+// doarc draws an elliptical arc with radii (w, h); a circular arc if h is zero
 func doarc(doc *gc.Canvas, x, y, w, h, a1, a2, sw float64, color string, opacity float64) {
-	c := gc.ColorLookup(color)
+	c := colorlookup(color)
 	c.A = setop(opacity)
-	doc.ArcLine(float32(x), float32(y), float32(w), radians(a1), radians(a2), float32(sw), c)
+	if h == 0 {
+		doc.ArcLine(float32(x), float32(y), float32(w), radians(a1), radians(a2), float32(sw), c)
+		return
+	}
+	doc.EllipticalArcLine(float32(x), float32(y), float32(w), float32(h), radians(a1), radians(a2), float32(sw), c)
 }
 
 // docurve draws a bezier curve
 func docurve(doc *gc.Canvas, xp1, yp1, xp2, yp2, xp3, yp3, sw float64, color string, opacity float64) {
-	c := gc.ColorLookup(color)
+	c := colorlookup(color)
 	c.A = setop(opacity)
 	doc.StrokedCurve(float32(xp1), float32(yp1), float32(xp2), float32(yp2), float32(xp3), float32(yp3), float32(sw), c)
 }
 
-// dorect draws a rectangle
-func dorect(doc *gc.Canvas, x, y, w, h float64, color string, opacity float64) {
-	c := gc.ColorLookup(color)
+// doarrow draws an arrow, curved if it has a control point
+func doarrow(doc *gc.Canvas, a arrow) {
+	c := colorlookup(a.Color)
+	c.A = setop(a.Opacity)
+	style := gc.ArrowStyle{Start: headmap[a.Tail], End: gc.FilledHead, Size: float32(a.Hs)}
+	if h, ok := headmap[a.Head]; ok {
+		style.End = h
+	}
+	xc, xerr := strconv.ParseFloat(a.Xc, 64)
+	yc, yerr := strconv.ParseFloat(a.Yc, 64)
+	if xerr == nil && yerr == nil {
+		doc.CurveArrow(float32(a.Xp1), float32(a.Yp1), float32(xc), float32(yc), float32(a.Xp2), float32(a.Yp2), float32(a.Sp), style, c)
+		return
+	}
+	doc.Arrow(float32(a.Xp1), float32(a.Yp1), float32(a.Xp2), float32(a.Yp2), float32(a.Sp), style, c)
+}
+
+// dorect draws a rectangle, with rounded corners if any radius is set
+func dorect(doc *gc.Canvas, x, y, w, h float64, r gc.Corners, color string, opacity float64) {
+	c := colorlookup(color)
 	c.A = setop(opacity)
-	doc.CenterRect(float32(x), float32(y), float32(w), float32(h), c)
+	if r == (gc.Corners{}) {
+		doc.CenterRect(float32(x), float32(y), float32(w), float32(h), c)
+		return
+	}
+	doc.CenterRoundedRect(float32(x), float32(y), float32(w), float32(h), r, c)
+}
+
+// corners parses a corner radius, or the upper left, upper right, lower right and lower left radii
+func corners(s string) gc.Corners {
+	f := strings.Fields(s)
+	r := make([]float32, len(f))
+	for i := range f {
+		v, err := strconv.ParseFloat(f[i], 32)
+		if err != nil {
+			return gc.Corners{}
+		}
+		r[i] = float32(v)
+	}
+	switch len(r) {
+	case 1:
+		return gc.Radius(r[0])
+	case 4:
+		return gc.Corners{UpperLeft: r[0], UpperRight: r[1], LowerRight: r[2], LowerLeft: r[3]}
+	}
+	return gc.Corners{}
 }
 
 // doellipse draws an ellipse
 func doellipse(doc *gc.Canvas, x, y, w, h float64, color string, opacity float64) {
-	c := gc.ColorLookup(color)
+	c := colorlookup(color)
 	c.A = setop(opacity)
 	doc.Ellipse(float32(x), float32(y), float32(w/2), float32(h/2), c)
 }
@@ -208,7 +270,7 @@ func dopoly(doc *gc.Canvas, xc, yc string, cw, ch float64, color string, opacity
 			py[i] = float32(y)
 		}
 	}
-	c := gc.ColorLookup(color)
+	c := colorlookup(color)
 	c.A = setop(opacity)
 	doc.Polygon(px, py, c)
 }
@@ -216,35 +278,33 @@ func dopoly(doc *gc.Canvas, xc, yc string, cw, ch float64, color string, opacity
 // dotext places text elements on the canvas according to type
 func dotext(doc *gc.Canvas, x, y, fs, wp, rotation, spacing float64, tdata, font, align, ttype, color string, opacity float64) {
 	td := strings.Split(tdata, "\n")
-	c := gc.ColorLookup(color)
-	var tstack op.TransformStack
+	doc.Push()
+	defer doc.Pop()
 	if rotation > 0 {
-		tstack = doc.Rotate(float32(x), float32(y), float32(rotation*(math.Pi/180)))
+		doc.Rotate(float32(x), float32(y), float32(rotation*(math.Pi/180)))
 	}
 	if ttype == "code" {
 		font = "mono"
 		ch := float64(len(td)) * spacing * fs
 		bx := (x + (wp / 2))
 		by := (y - (ch / 2)) + (spacing * fs)
-		dorect(doc, bx, by, wp+fs, ch+fs, "rgb(240,240,240)", 100)
+		dorect(doc, bx, by, wp+fs, ch+fs, gc.Radius(float32(fs/2)), "rgb(240,240,240)", 100)
 	}
 	if ttype == "block" {
 		textwrap(doc, x, y, fs, wp, tdata, color, opacity)
 	} else {
+		textstyle(doc, fs, colorlookup(color), font, align)
 		ls := spacing * fs
 		for _, t := range td {
-			showtext(doc, x, y, t, fs, c, font, align)
+			showtext(doc, x, y, t)
 			y -= ls
 		}
 	}
-	if rotation > 0 {
-		gc.EndTransform(tstack)
-	}
 }
 
 // textwrap places and wraps text at a width
 func textwrap(doc *gc.Canvas, x, y, fs, wp float64, tdata, color string, opacity float64) {
-	c := gc.ColorLookup(color)
+	c := colorlookup(color)
 	c.A = setop(opacity)
 	doc.TextWrap(float32(x), float32(y), float32(fs), float32(wp), tdata, c)
 }
@@ -254,76 +314,74 @@ func whitespace(r rune) bool {
 	return r == ' ' || r == '\n' || r == '\t'
 }
 
-// loadfont loads a font at the specified size
-func loadfont(doc *gc.Canvas, s string, size float64) {
-	doc.Theme.Face = font.Typeface(s)
-	//fmt.Fprintf(os.Stderr, "loadfont: %v\n", doc.Theme.Face)
-}
-
-// showtext places fully attributed text at the specified location
-func showtext(doc *gc.Canvas, x, y float64, s string, fs float64, color color.NRGBA, font, align string) {
-	loadfont(doc, font, fs)
-	tx := float32(x)
-	ty := float32(y)
-	tfs := float32(fs)
+// textstyle sets the text size, color, font and alignment
+func textstyle(doc *gc.Canvas, fs float64, color color.NRGBA, font, align string) {
+	doc.SetFill(color)
+	doc.SetFont(font, float32(fs))
 	switch align {
 	case "center", "middle", "mid", "c":
-		doc.TextMid(tx, ty, tfs, s, color)
+		doc.SetAlign(text.Middle)
 	case "right", "end", "e":
-		doc.TextEnd(tx, ty, tfs, s, color)
+		doc.SetAlign(text.End)
 	default:
-		doc.Text(tx, ty, tfs, s, color)
+		doc.SetAlign(text.Start)
 	}
 }
 
+// showtext places text at the specified location, using the current text style
+func showtext(doc *gc.Canvas, x, y float64, s string) {
+	doc.DrawText(float32(x), float32(y), s)
+}
+
 // dolist places lists on the canvas
 func dolist(doc *gc.Canvas, cw, x, y, fs, lwidth, rotation, spacing float64, list []deck.ListItem, font, ltype, align, color string, opacity float64) {
 	if font == "" {
 		font = "sans"
 	}
-	var tstack op.TransformStack
+	doc.Push()
+	defer doc.Pop()
 	if rotation > 0 {
-		tstack = doc.Rotate(float32(x), float32(y), float32(rotation*(math.Pi/180)))
+		doc.Rotate(float32(x), float32(y), float32(rotation*(math.Pi/180)))
 	}
+	textstyle(doc, fs, colorlookup(color), font, align)
 
 	ls := listspacing * fs
 	for i, tl := range list {
-		loadfont(doc, font, fs)
-		c := gc.ColorLookup(color)
+		c := colorlookup(color)
 		if len(tl.Color) > 0 {
-			c = gc.ColorLookup(tl.Color)
+			c = colorlookup(tl.Color)
 		}
+		doc.SetFill(c)
 		switch ltype {
 		case "number":
-			showtext(doc, x, y, fmt.Sprintf("%d. ", i+1)+tl.ListText, fs, c, font, align)
+			showtext(doc, x, y, fmt.Sprintf("%d. ", i+1)+tl.ListText)
 		case "bullet":
-			doc.Circle(float32(x), float32(y+fs/3), float32(fs/4), c)
-			showtext(doc, x+fs, y, tl.ListText, fs, c, font, align)
-		case "center":
-			showtext(doc, x, y, tl.ListText, fs, c, font, align)
+			doc.DrawCircle(float32(x), float32(y+fs/3), float32(fs/4))
+			showtext(doc, x+fs, y, tl.ListText)
 		default:
-			showtext(doc, x, y, tl.ListText, fs, c, font, align)
+			showtext(doc, x, y, tl.ListText)
 		}
 		y -= ls
 	}
-	if rotation > 0 {
-		gc.EndTransform(tstack)
-	}
 }
 
 // showslide shows a slide
-func showslide(doc *gc.Canvas, d *deck.Deck, n int, layers string) {
+func showslide(doc *gc.Canvas, d *deck.Deck, extra []slideExtras, n int, layers string) {
 	if n < 0 || n > len(d.Slide)-1 {
 		return
 	}
 	cw := float64(d.Canvas.Width)
 	ch := float64(d.Canvas.Height)
 	slide := d.Slide[n]
+	var ext slideExtras
+	if n < len(extra) {
+		ext = extra[n]
+	}
 	// set default background
 	if slide.Bg == "" {
 		slide.Bg = "white"
 	}
-	doc.Background(gc.ColorLookup(slide.Bg))
+	doc.Background(colorlookup(slide.Bg))
 
 	if slide.GradPercent <= 0 || slide.GradPercent > 100 {
 		slide.GradPercent = 100
@@ -384,24 +442,28 @@ func showslide(doc *gc.Canvas, d *deck.Deck, n int, layers string) {
 						cx = cimx - pct((iw/2), cw)
 					}
 					cy = im.Yp - (ih/2)/ch*100 - (capsize * 2)
-					showtext(doc, cx, cy, im.Caption, capsize, gc.ColorLookup(im.Color), im.Font, im.Align)
+					doc.Push()
+					textstyle(doc, capsize, colorlookup(im.Color), im.Font, im.Align)
+					showtext(doc, cx, cy, im.Caption)
+					doc.Pop()
 				}
 			}
 		// every graphic on the slide
 		// rect
 		case "rect":
 
-			for _, rect := range slide.Rect {
+			for i, rect := range slide.Rect {
 				if rect.Color == "" {
 					rect.Color = defaultColor
 				}
+				var r gc.Corners
+				if i < len(ext.Rect) {
+					r = corners(ext.Rect[i].Radius)
+				}
 				if rect.Hr == 100 {
-					c := gc.ColorLookup(rect.Color)
-					c.A = setop(rect.Opacity)
-					doc.CenterRect(float32(rect.Xp), float32(rect.Yp), float32(rect.Wp), float32((rect.Wp)*(cw/ch)), c)
-				} else {
-					dorect(doc, rect.Xp, rect.Yp, rect.Wp, rect.Hp, rect.Color, rect.Opacity)
+					rect.Hp = rect.Wp * (cw / ch)
 				}
+				dorect(doc, rect.Xp, rect.Yp, rect.Wp, rect.Hp, r, rect.Color, rect.Opacity)
 			}
 		// ellipse
 		case "ellipse":
@@ -410,7 +472,7 @@ func showslide(doc *gc.Canvas, d *deck.Deck, n int, layers string) {
 					ellipse.Color = defaultColor
 				}
 				if ellipse.Hr == 100 {
-					c := gc.ColorLookup(ellipse.Color)
+					c := colorlookup(ellipse.Color)
 					c.A = setop(ellipse.Opacity)
 					doc.Circle(float32(ellipse.Xp), float32(ellipse.Yp), float32(ellipse.Wp/2), c)
 				} else {
@@ -436,6 +498,9 @@ func showslide(doc *gc.Canvas, d *deck.Deck, n int, layers string) {
 				}
 				w := arc.Wp
 				h := arc.Hp
+				if arc.Hr > 0 { // height relative to the width
+					h = w * (arc.Hr / 100) * (cw / ch)
+				}
 				if arc.Sp == 0 {
 					arc.Sp = 0.2
 				}
@@ -452,6 +517,17 @@ func showslide(doc *gc.Canvas, d *deck.Deck, n int, layers string) {
 				}
 				doline(doc, line.Xp1, line.Yp1, line.Xp2, line.Yp2, line.Sp, line.Color, line.Opacity)
 			}
+		// arrow
+		case "arrow":
+			for _, a := range ext.Arrow {
+				if a.Color == "" {
+					a.Color = defaultColor
+				}
+				if a.Sp == 0 {
+					a.Sp = 0.2
+				}
+				doarrow(doc, a)
+			}
 		// polygon
 		case "poly":
 			for _, poly := range slide.Polygon {
@@ -515,12 +591,66 @@ func imageInfo(s string) (image.Image, error) {
 	return im, nil
 }
 
+// slideExtras holds the slide elements and attributes that are not read by the deck package
+type slideExtras struct {
+	Rect []struct {
+		Radius string `xml:"radius,attr"` // corner radius, or four radii starting at the upper left
+	} `xml:"rect"`
+	Arrow []arrow `xml:"arrow"`
+}
+
+// arrow is a line or quadratic curve with arrow heads:
+// <arrow xp1="10" yp1="50" xp2="90" yp2="50" head="filled" tail="none"/>
+// <arrow xp1="10" yp1="50" xc="50" yc="80" xp2="90" yp2="50" head="open"/>
+type arrow struct {
+	Xp1     float64 `xml:"xp1,attr"`
+	Yp1     float64 `xml:"yp1,attr"`
+	Xp2     float64 `xml:"xp2,attr"`
+	Yp2     float64 `xml:"yp2,attr"`
+	Xc      string  `xml:"xc,attr"` // control point, for curved arrows
+	Yc      string  `xml:"yc,attr"`
+	Sp      float64 `xml:"sp,attr"` // line thickness
+	Hs      float64 `xml:"hs,attr"` // head size
+	Head    string  `xml:"head,attr"`
+	Tail    string  `xml:"tail,attr"`
+	Color   string  `xml:"color,attr"`
+	Opacity float64 `xml:"opacity,attr"`
+}
+
+// headmap maps arrow head names
+var headmap = map[string]gc.ArrowHead{
+	"none":    gc.NoHead,
+	"open":    gc.OpenHead,
+	"filled":  gc.FilledHead,
+	"diamond": gc.DiamondHead,
+	"circle":  gc.CircleHead,
+	"bar":     gc.BarHead,
+}
+
 // ReadDeck reads the deck file, rendering to the canvas
-func readDeck(filename string, w, h float32) (deck.Deck, error) {
-	d, err := deck.Read(filename, int(w), int(h))
+func readDeck(filename string, w, h float32) (deck.Deck, []slideExtras, error) {
+	var d deck.Deck
+	var extra struct {
+		Slide []slideExtras `xml:"slide"`
+	}
+	var b []byte
+	var err error
+	if filename == "-" {
+		b, err = io.ReadAll(os.Stdin)
+	} else {
+		b, err = os.ReadFile(filename)
+	}
+	if err != nil {
+		return d, nil, err
+	}
+	d, err = deck.ReadDeck(io.NopCloser(bytes.NewReader(b)), int(w), int(h))
 	d.Canvas.Width = int(w)
 	d.Canvas.Height = int(h)
-	return d, err
+	if err != nil {
+		return d, nil, err
+	}
+	err = xml.Unmarshal(b, &extra)
+	return d, extra.Slide, err
 }
 
 // modtime returns the modification time of a file
@@ -534,16 +664,10 @@ func modtime(filename string) (time.Time, error) {
 
 // ngrid makes a numbered grid
 func ngrid(c *gc.Canvas, interval, ts float32, color color.NRGBA) {
-	color.A = 75
-	c.Grid(0, 0, 100, 100, 0.1, interval, color)
-	var x, y float32
-	color.A = 220
-	for x = interval; x < 100; x += interval {
-		c.CText(x, ts, ts, fmt.Sprintf("%0.f", x), color)
-	}
-	for y = interval; y < 100; y += interval {
-		c.CText(ts, y-(ts/2), ts, fmt.Sprintf("%0.f", y), color)
-	}
+	style := gc.GridStyle{Major: interval, MajorSize: 0.1, MajorColor: color, LabelSize: ts, LabelColor: color}
+	style.MajorColor.A = 75
+	style.LabelColor.A = 220
+	c.LabeledGrid(0, 0, 100, 100, style)
 }
 
 // readfonts creates a collection of fonts based on names in a font directory
@@ -585,7 +709,7 @@ func main() {
 
 	flag.StringVar(&title, "title", "", "slide title")
 	flag.StringVar(&pagesize, "pagesize", "Letter", "pagesize: w,h, or one of: Letter, Legal, Tabloid, A3, A4, A5, ArchA, 4R, Index, Widescreen")
-	flag.StringVar(&layers, "layers", "image:rect:ellipse:curve:arc:line:poly:text:list", "Drawing order")
+	flag.StringVar(&layers, "layers", "image:rect:ellipse:curve:arc:line:arrow:poly:text:list", "Drawing order")
 	flag.IntVar(&initpage, "page", 1, "initial page")
 	flag.StringVar(&sans, "sans", "Go-Regular", "sans font")
 	flag.StringVar(&serif, "serif", "Go-Smallcaps", "serif font")
@@ -708,8 +832,9 @@ func slidedeck(s string, initpage int, filename, pagesize, fontdir, layers strin
 	var btime, ftime time.Time
 	var err error
 	var deck deck.Deck
+	var extra []slideExtras
 	width, height := pagedim(pagesize)
-	deck, err = readDeck(filename, width, height)
+	deck, extra, err = readDeck(filename, width, height)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
@@ -748,9 +873,9 @@ func slidedeck(s string, initpage int, filename, pagesize, fontdir, layers strin
 			}
 			deck.Canvas.Width = int(e.Size.X)
 			deck.Canvas.Height = int(e.Size.Y)
-			showslide(canvas, &deck, slidenumber, layers)
+			showslide(canvas, &deck, extra, slidenumber, layers)
 			if gridstate {
-				ngrid(canvas, 5, 1, gc.ColorLookup(deck.Slide[slidenumber].Fg))
+				ngrid(canvas, 5, 1, colorlookup(deck.Slide[slidenumber].Fg))
 			}
 			ftime, err = modtime(filename)
 			if err != nil {
@@ -758,7 +883,7 @@ func slidedeck(s string, initpage int, filename, pagesize, fontdir, layers strin
 				os.Exit(1)
 			}
 			if ftime.After(btime) {
-				deck, err = readDeck(filename, float32(e.Size.X), float32(e.Size.Y))
+				deck, extra, err = readDeck(filename, float32(e.Size.X), float32(e.Size.Y))
 				if err != nil {
 					fmt.Fprintf(os.Stderr, "%v\n", err)
 					os.Exit(1)
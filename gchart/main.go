@@ -3,7 +3,10 @@ package main
 
 import (
 	"flag"
+	"fmt"
+	"image/color"
 	"io"
+	"math"
 	"os"
 	"strconv"
 	"strings"
@@ -18,11 +21,11 @@ import (
 )
 
 type chartOptions struct {
-	top, bottom, left, right, minvalue, maxvalue                                           float64
-	barwidth, linewidth, linespacing, dotsize, textsize, piesize, ty, frameOp, opacity     float64
-	bgcolor, dcolor, labelcolor, valuecolor, chartitle, yaxfmt, yrange, fontname, valuefmt string
-	xlabel                                                                                 int
-	zb, line, bar, hbar, scatter, area, pie, lego, dot, wbar, showtitle, showgrid          bool
+	top, bottom, left, right, minvalue, maxvalue                                                             float64
+	barwidth, linewidth, linespacing, dotsize, textsize, piesize, holesize, ty, tx, frameOp, frameR, opacity float64
+	bgcolor, dcolor, labelcolor, valuecolor, chartitle, ytitle, yaxfmt, yrange, fontname, valuefmt, pattern  string
+	xlabel                                                                                                   int
+	zb, line, bar, hbar, scatter, area, pie, lego, dot, wbar, showtitle, showgrid                            bool
 }
 
 // loadfont loads a font collection from a name
@@ -83,6 +86,36 @@ func yr(yrange string, dmin, dmax float64) (float64, float64, float64) {
 	return min, max, step
 }
 
+// colorlookup returns the color for a specification, reporting errors
+func colorlookup(s string) color.NRGBA {
+	_, err := giocanvas.ParseColor(s)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+	}
+	return giocanvas.ColorLookup(s)
+}
+
+// patternmap maps names to fill patterns
+var patternmap = map[string]giocanvas.Pattern{
+	"stripes":    {Kind: giocanvas.Stripes, Angle: math.Pi / 4},
+	"hstripes":   {Kind: giocanvas.Stripes},
+	"vstripes":   {Kind: giocanvas.Stripes, Angle: math.Pi / 2},
+	"crosshatch": {Kind: giocanvas.Crosshatch, Angle: math.Pi / 4},
+	"dots":       {Kind: giocanvas.Dots, Spacing: 1.5, Width: 0.6},
+	"checker":    {Kind: giocanvas.Checker, Spacing: 1},
+}
+
+// patterns returns the fill patterns named in a space separated list
+func patterns(s string) []giocanvas.Pattern {
+	var p []giocanvas.Pattern
+	for _, name := range strings.Fields(s) {
+		if pat, ok := patternmap[name]; ok {
+			p = append(p, pat)
+		}
+	}
+	return p
+}
+
 // gchart draws a chart
 func gchart(w, h int, data chart.ChartBox, opts chartOptions) {
 	width := float32(w)
@@ -94,14 +127,15 @@ func gchart(w, h int, data chart.ChartBox, opts chartOptions) {
 	win.Option(apptitle, appsize)
 
 	// Define the colors
-	datacolor := giocanvas.ColorLookup(opts.dcolor)
-	labelcolor := giocanvas.ColorLookup(opts.labelcolor)
-	bgcolor := giocanvas.ColorLookup(opts.bgcolor)
+	datacolor := colorlookup(opts.dcolor)
+	labelcolor := colorlookup(opts.labelcolor)
+	bgcolor := colorlookup(opts.bgcolor)
 
 	// Set the chart attributes
 	data.Zerobased = opts.zb
 	data.Top, data.Bottom = opts.top, opts.bottom
 	data.Left, data.Right = opts.left, opts.right
+	data.Patterns = patterns(opts.pattern)
 
 	// set the font
 	fc := loadfont(opts.fontname)
@@ -114,6 +148,7 @@ func gchart(w, h int, data chart.ChartBox, opts chartOptions) {
 
 			// Draw the data
 			data.Color = datacolor
+			data.Radius = opts.frameR
 			if opts.frameOp > 0 {
 				data.Frame(canvas, opts.frameOp)
 			}
@@ -139,7 +174,7 @@ func gchart(w, h int, data chart.ChartBox, opts chartOptions) {
 				data.Area(canvas, opts.opacity)
 			}
 			if opts.pie {
-				data.Pie(canvas, opts.piesize)
+				data.Donut(canvas, opts.piesize, opts.holesize)
 			}
 			if opts.lego {
 				data.Lego(canvas, opts.dotsize)
@@ -169,6 +204,9 @@ func gchart(w, h int, data chart.ChartBox, opts chartOptions) {
 			if opts.showtitle && len(data.Title) > 0 {
 				data.CTitle(canvas, opts.textsize*1.5, opts.ty)
 			}
+			if len(opts.ytitle) > 0 {
+				data.YTitle(canvas, opts.textsize*1.2, opts.tx, opts.ytitle)
+			}
 
 			e.Frame(canvas.Context.Ops)
 
@@ -199,6 +237,8 @@ Options     Default               Description
 -valuecolor  "rgb(128,0,0)"       value color
 -opacity     40                   opacity for area and wbar charts
 -frame       0                    frame opacity
+-frameradius 0                    frame corner radius
+-pattern     ""                   fill patterns (stripes, hstripes, vstripes, crosshatch, dots, checker)
 -font        ""                   specify font file (\"\": default)
 .....................................................................
 -h           1000                 canvas height
@@ -215,10 +255,13 @@ Options     Default               Description
 -linewidth   0.25                 line width
 -ls          2                    line spacing
 -piesize     20                   pie chart radius
+-holesize    0                    pie chart hole radius (donut chart)
 -textsize    1.0                  text size
 .....................................................................
 -chartitle   ""                   chart title
 -ty          5                    title position relative to the top
+-ytitle      ""                   y axis title
+-tx          10                   y axis title position relative to the left
 -xlabel      1                    x-xaxis label interval (0: no labels)
 -yfmt        "%v"                 yaxis format
 -vfmt        ""                   value format ("": no values)
@@ -254,6 +297,7 @@ func main() {
 	flag.Float64Var(&opts.linewidth, "linewidth", 0.25, "line width")
 	flag.Float64Var(&opts.linespacing, "ls", opts.barwidth*4, "line spacing")
 	flag.Float64Var(&opts.piesize, "piesize", 20, "pie chart radius")
+	flag.Float64Var(&opts.holesize, "holesize", 0, "pie chart hole radius (donut chart)")
 	flag.Float64Var(&opts.textsize, "textsize", 1.0, "text size")
 	flag.Float64Var(&opts.minvalue, "min", -1, "text size")
 	flag.Float64Var(&opts.maxvalue, "max", -1, "text size")
@@ -270,6 +314,8 @@ func main() {
 	flag.IntVar(&opts.xlabel, "xlabel", 1, "x-axis label interval")
 	flag.StringVar(&opts.yrange, "yrange", "", "y axis range (min,max,step)")
 	flag.StringVar(&opts.chartitle, "chartitle", "", "chart title")
+	flag.StringVar(&opts.ytitle, "ytitle", "", "y axis title")
+	flag.Float64Var(&opts.tx, "tx", 10, "y axis title position relative to the left")
 	flag.StringVar(&opts.valuefmt, "vfmt", "", "value format (\"\": no values)")
 	flag.StringVar(&opts.yaxfmt, "yfmt", "%v", "yaxis format (\"\" no y axis)")
 	// colors and opacities
@@ -279,6 +325,8 @@ func main() {
 	flag.StringVar(&opts.labelcolor, "labelcolor", "rgb(100,100,100)", "label color")
 	flag.StringVar(&opts.valuecolor, "valuecolor", "rgb(128,100,0)", "value color")
 	flag.Float64Var(&opts.frameOp, "frame", 0, "frame opacity (0: no frame)")
+	flag.Float64Var(&opts.frameR, "frameradius", 0, "frame corner radius")
+	flag.StringVar(&opts.pattern, "pattern", "", "space separated fill patterns (stripes, hstripes, vstripes, crosshatch, dots, checker)")
 	flag.Float64Var(&opts.opacity, "opacity", 40, "% opacity for area and wbar charts")
 	// on-off flags
 	flag.BoolVar(&opts.showtitle, "title", true, "show the title")
@@ -0,0 +1,367 @@
+package giocanvas
+
+import (
+	"image/color"
+	"math"
+	"sort"
+
+	"gioui.org/f32"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+)
+
+// Geometry in percent coordinates: x increases left to right, y increases bottom to top.
+// Distances and areas are in percent units, and are not to scale on non-square canvases.
+
+// Point is a location in percent coordinates
+type Point struct {
+	X, Y float32
+}
+
+// Pt is shorthand for Point{x, y}
+func Pt(x, y float32) Point {
+	return Point{X: x, Y: y}
+}
+
+// Add returns p+q
+func (p Point) Add(q Point) Point {
+	return Point{p.X + q.X, p.Y + q.Y}
+}
+
+// Sub returns p-q
+func (p Point) Sub(q Point) Point {
+	return Point{p.X - q.X, p.Y - q.Y}
+}
+
+// Mul returns p scaled by factor
+func (p Point) Mul(factor float32) Point {
+	return Point{p.X * factor, p.Y * factor}
+}
+
+// Dist returns the distance between p and q
+func (p Point) Dist(q Point) float32 {
+	return float32(math.Hypot(float64(q.X-p.X), float64(q.Y-p.Y)))
+}
+
+// Angle returns the angle (radians) of the line from p to q
+func (p Point) Angle(q Point) float64 {
+	return math.Atan2(float64(q.Y-p.Y), float64(q.X-p.X))
+}
+
+// DistToSegment returns the distance from p to the line segment from a to b
+func (p Point) DistToSegment(a, b Point) float32 {
+	dx, dy := float64(b.X-a.X), float64(b.Y-a.Y)
+	l2 := dx*dx + dy*dy
+	if l2 == 0 {
+		return p.Dist(a)
+	}
+	t := clamp((float64(p.X-a.X)*dx+float64(p.Y-a.Y)*dy)/l2, 0, 1)
+	return p.Dist(Point{a.X + float32(t*dx), a.Y + float32(t*dy)})
+}
+
+// LineIntersection returns the intersection of the line through a1 and a2
+// with the line through b1 and b2. ok is false for parallel lines.
+func LineIntersection(a1, a2, b1, b2 Point) (p Point, ok bool) {
+	p, _, _, ok = intersect(a1, a2, b1, b2)
+	return p, ok
+}
+
+// SegmentIntersection returns the intersection of the line segments (a1, a2) and (b1, b2).
+// ok is false if they do not cross.
+func SegmentIntersection(a1, a2, b1, b2 Point) (p Point, ok bool) {
+	p, t, u, ok := intersect(a1, a2, b1, b2)
+	if !ok || t < 0 || t > 1 || u < 0 || u > 1 {
+		return Point{}, false
+	}
+	return p, true
+}
+
+// intersect returns the intersection of lines (a1, a2) and (b1, b2),
+// with its position along each, where 0 is the first point and 1 the second
+func intersect(a1, a2, b1, b2 Point) (Point, float64, float64, bool) {
+	rx, ry := float64(a2.X-a1.X), float64(a2.Y-a1.Y)
+	sx, sy := float64(b2.X-b1.X), float64(b2.Y-b1.Y)
+	d := rx*sy - ry*sx
+	if math.Abs(d) < 1e-12 {
+		return Point{}, 0, 0, false
+	}
+	qx, qy := float64(b1.X-a1.X), float64(b1.Y-a1.Y)
+	t := (qx*sy - qy*sx) / d
+	u := (qx*ry - qy*rx) / d
+	return Point{a1.X + float32(t*rx), a1.Y + float32(t*ry)}, t, u, true
+}
+
+// Rect is a rectangle from Min (lower left) to Max (upper right)
+type Rect struct {
+	Min, Max Point
+}
+
+// R makes a rectangle from its upper left corner (x, y) and size (w, h), as used by CornerRect
+func R(x, y, w, h float32) Rect {
+	return Rect{Min: Point{x, y - h}, Max: Point{x + w, y}}
+}
+
+// W returns the width of r
+func (r Rect) W() float32 {
+	return r.Max.X - r.Min.X
+}
+
+// H returns the height of r
+func (r Rect) H() float32 {
+	return r.Max.Y - r.Min.Y
+}
+
+// Center returns the center of r
+func (r Rect) Center() Point {
+	return Point{(r.Min.X + r.Max.X) / 2, (r.Min.Y + r.Max.Y) / 2}
+}
+
+// Empty reports whether r has no area
+func (r Rect) Empty() bool {
+	return r.Min.X >= r.Max.X || r.Min.Y >= r.Max.Y
+}
+
+// Contains reports whether p is inside r
+func (r Rect) Contains(p Point) bool {
+	return p.X >= r.Min.X && p.X <= r.Max.X && p.Y >= r.Min.Y && p.Y <= r.Max.Y
+}
+
+// Intersect returns the largest rectangle inside both r and s, or an empty rectangle
+func (r Rect) Intersect(s Rect) Rect {
+	i := Rect{
+		Min: Point{max32(r.Min.X, s.Min.X), max32(r.Min.Y, s.Min.Y)},
+		Max: Point{min32(r.Max.X, s.Max.X), min32(r.Max.Y, s.Max.Y)},
+	}
+	if i.Empty() {
+		return Rect{}
+	}
+	return i
+}
+
+// Union returns the smallest rectangle containing r and s
+func (r Rect) Union(s Rect) Rect {
+	if r.Empty() {
+		return s
+	}
+	if s.Empty() {
+		return r
+	}
+	return Rect{
+		Min: Point{min32(r.Min.X, s.Min.X), min32(r.Min.Y, s.Min.Y)},
+		Max: Point{max32(r.Max.X, s.Max.X), max32(r.Max.Y, s.Max.Y)},
+	}
+}
+
+// Polygon returns the corners of r, counterclockwise from the lower left
+func (r Rect) Polygon() Polygon {
+	return Polygon{r.Min, {r.Max.X, r.Min.Y}, r.Max, {r.Min.X, r.Max.Y}}
+}
+
+// Polygon is a closed shape defined by its vertices
+type Polygon []Point
+
+// NewPolygon makes a polygon from lists of x and y coordinates, as used by the Polygon method
+func NewPolygon(x, y []float32) Polygon {
+	n := len(x)
+	if len(y) < n {
+		n = len(y)
+	}
+	p := make(Polygon, n)
+	for i := 0; i < n; i++ {
+		p[i] = Point{x[i], y[i]}
+	}
+	return p
+}
+
+// XY returns the lists of x and y coordinates of p, as used by the Polygon method
+func (p Polygon) XY() ([]float32, []float32) {
+	x, y := make([]float32, len(p)), make([]float32, len(p))
+	for i, v := range p {
+		x[i], y[i] = v.X, v.Y
+	}
+	return x, y
+}
+
+// Bounds returns the smallest rectangle containing p
+func (p Polygon) Bounds() Rect {
+	if len(p) == 0 {
+		return Rect{}
+	}
+	r := Rect{p[0], p[0]}
+	for _, v := range p[1:] {
+		r.Min.X, r.Min.Y = min32(r.Min.X, v.X), min32(r.Min.Y, v.Y)
+		r.Max.X, r.Max.Y = max32(r.Max.X, v.X), max32(r.Max.Y, v.Y)
+	}
+	return r
+}
+
+// signedArea returns the area of p, positive if the vertices are counterclockwise
+func (p Polygon) signedArea() float64 {
+	var a float64
+	n := len(p)
+	for i := 0; i < n; i++ {
+		v, w := p[i], p[(i+1)%n]
+		a += float64(v.X)*float64(w.Y) - float64(w.X)*float64(v.Y)
+	}
+	return a / 2
+}
+
+// Area returns the area of p
+func (p Polygon) Area() float32 {
+	return float32(math.Abs(p.signedArea()))
+}
+
+// Clockwise reports whether the vertices of p are in clockwise order
+func (p Polygon) Clockwise() bool {
+	return p.signedArea() < 0
+}
+
+// Reverse returns p with the order of its vertices reversed
+func (p Polygon) Reverse() Polygon {
+	r := make(Polygon, len(p))
+	for i, v := range p {
+		r[len(p)-1-i] = v
+	}
+	return r
+}
+
+// Centroid returns the center of mass of p
+func (p Polygon) Centroid() Point {
+	n := len(p)
+	if n == 0 {
+		return Point{}
+	}
+	a := p.signedArea()
+	if math.Abs(a) < 1e-9 { // no area, use the average of the vertices
+		var sx, sy float64
+		for _, v := range p {
+			sx += float64(v.X)
+			sy += float64(v.Y)
+		}
+		return Point{float32(sx / float64(n)), float32(sy / float64(n))}
+	}
+	var cx, cy float64
+	for i := 0; i < n; i++ {
+		v, w := p[i], p[(i+1)%n]
+		cross := float64(v.X)*float64(w.Y) - float64(w.X)*float64(v.Y)
+		cx += float64(v.X+w.X) * cross
+		cy += float64(v.Y+w.Y) * cross
+	}
+	return Point{float32(cx / (6 * a)), float32(cy / (6 * a))}
+}
+
+// Contains reports whether pt is inside p (using the even-odd rule)
+func (p Polygon) Contains(pt Point) bool {
+	in := false
+	n := len(p)
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		a, b := p[i], p[j]
+		if (a.Y > pt.Y) != (b.Y > pt.Y) && pt.X < (b.X-a.X)*(pt.Y-a.Y)/(b.Y-a.Y)+a.X {
+			in = !in
+		}
+	}
+	return in
+}
+
+// Offset returns p with its edges moved outward by d, or inward if d is negative.
+// Corners are mitered; large insets of concave polygons may self-intersect.
+func (p Polygon) Offset(d float32) Polygon {
+	n := len(p)
+	if n < 3 {
+		return p
+	}
+	if p.Clockwise() {
+		d = -d
+	}
+	// move each edge along its outward normal (right of the edge for counterclockwise polygons)
+	shift := func(a, b Point) (Point, Point) {
+		l := a.Dist(b)
+		if l == 0 {
+			return a, b
+		}
+		nx, ny := (b.Y-a.Y)/l*d, -(b.X-a.X)/l*d
+		return Point{a.X + nx, a.Y + ny}, Point{b.X + nx, b.Y + ny}
+	}
+	o := make(Polygon, n)
+	for i := 0; i < n; i++ {
+		a1, a2 := shift(p[(i+n-1)%n], p[i])
+		b1, b2 := shift(p[i], p[(i+1)%n])
+		if v, ok := LineIntersection(a1, a2, b1, b2); ok {
+			o[i] = v
+		} else { // collinear edges
+			o[i] = b1
+		}
+	}
+	return o
+}
+
+// ConvexHull returns the smallest convex polygon containing the points, counterclockwise
+func ConvexHull(points []Point) Polygon {
+	pts := append([]Point(nil), points...)
+	sort.Slice(pts, func(i, j int) bool {
+		if pts[i].X == pts[j].X {
+			return pts[i].Y < pts[j].Y
+		}
+		return pts[i].X < pts[j].X
+	})
+	if len(pts) < 3 {
+		return pts
+	}
+	cross := func(o, a, b Point) float32 {
+		return (a.X-o.X)*(b.Y-o.Y) - (a.Y-o.Y)*(b.X-o.X)
+	}
+	hull := make(Polygon, 0, 2*len(pts))
+	for _, v := range pts { // lower hull
+		for len(hull) >= 2 && cross(hull[len(hull)-2], hull[len(hull)-1], v) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, v)
+	}
+	for i, lower := len(pts)-2, len(hull)+1; i >= 0; i-- { // upper hull
+		v := pts[i]
+		for len(hull) >= lower && cross(hull[len(hull)-2], hull[len(hull)-1], v) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, v)
+	}
+	return hull[:len(hull)-1]
+}
+
+// Shape fills polygons as a single shape, using percentage-based measures.
+// Polygons wound in the opposite direction of the one containing them are holes,
+// as returned by Union, Intersection and Difference.
+func (c *Canvas) Shape(polygons []Polygon, fillcolor color.NRGBA) {
+	ops := c.Context.Ops
+	path := new(clip.Path)
+	path.Begin(ops)
+	for _, p := range polygons {
+		if len(p) < 3 {
+			continue
+		}
+		x, y := dimen(p[0].X, p[0].Y, c.Width, c.Height)
+		path.MoveTo(f32.Point{X: x, Y: y})
+		for _, v := range p[1:] {
+			x, y = dimen(v.X, v.Y, c.Width, c.Height)
+			path.LineTo(f32.Point{X: x, Y: y})
+		}
+		path.Close()
+	}
+	stack := clip.Outline{Path: path.End()}.Op().Push(ops)
+	paint.ColorOp{Color: fillcolor}.Add(ops)
+	paint.PaintOp{}.Add(ops)
+	stack.Pop()
+}
+
+func min32(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max32(a, b float32) float32 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -19,6 +19,10 @@ type Canvas struct {
 	Theme         *material.Theme
 	TextColor     color.NRGBA
 	Context       layout.Context
+	Style         Style
+	Direction     TextDirection // direction of text; DirectionAuto (the default) follows the text
+	saved         []state
+	applied       []applied
 }
 
 // setupCanvas sets up common canvas items
@@ -27,6 +31,7 @@ func setupCanvas(width, height float32, e app.FrameEvent, f []font.FontFace) *Ca
 	canvas.Width = width
 	canvas.Height = height
 	canvas.TextColor = color.NRGBA{0, 0, 0, 255}
+	canvas.Style = DefaultStyle
 	theme := material.NewTheme()
 	theme.Shaper = text.NewShaper(text.NoSystemFonts(), text.WithCollection(f))
 	canvas.Theme = theme
@@ -1,7 +1,24 @@
 package giocanvas
 
 import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+
+	"gioui.org/app"
+	"gioui.org/f32"
+	"gioui.org/io/key"
+	"github.com/ajstarks/giocanvas/golden"
 )
 
 func BenchmarkC0(b *testing.B) {
@@ -33,3 +50,1031 @@ func BenchmarkC4(b *testing.B) {
 		ColorLookup("rgb(100,100,100,100)")
 	}
 }
+
+func BenchmarkParseColor(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		ParseColor("oklch(62.8% 0.2577 29.23 / 50%)")
+	}
+}
+
+// batch benchmarks draw the same shapes one at a time, and together
+const nbatch = 1000
+
+// batchCoords returns coordinates for the batch benchmarks
+func batchCoords() ([]float32, []float32) {
+	x, y := make([]float32, nbatch), make([]float32, nbatch)
+	for i := range x {
+		x[i], y[i] = float32(i%40)*2.5, float32(i/40)*4
+	}
+	return x, y
+}
+
+func BenchmarkCircle(b *testing.B) {
+	canvas := NewCanvas(1000, 1000, app.FrameEvent{})
+	x, y := batchCoords()
+	fillcolor := color.NRGBA{128, 0, 0, 255}
+	for n := 0; n < b.N; n++ {
+		canvas.Context.Ops.Reset()
+		for i := range x {
+			canvas.Circle(x[i], y[i], 1, fillcolor)
+		}
+	}
+}
+
+func BenchmarkCircles(b *testing.B) {
+	canvas := NewCanvas(1000, 1000, app.FrameEvent{})
+	x, y := batchCoords()
+	fillcolor := color.NRGBA{128, 0, 0, 255}
+	for n := 0; n < b.N; n++ {
+		canvas.Context.Ops.Reset()
+		canvas.Circles(x, y, []float32{1}, fillcolor)
+	}
+}
+
+func BenchmarkRect(b *testing.B) {
+	canvas := NewCanvas(1000, 1000, app.FrameEvent{})
+	x, y := batchCoords()
+	fillcolor := color.NRGBA{128, 0, 0, 255}
+	for n := 0; n < b.N; n++ {
+		canvas.Context.Ops.Reset()
+		for i := range x {
+			canvas.Rect(x[i], y[i], 2, 3, fillcolor)
+		}
+	}
+}
+
+func BenchmarkRects(b *testing.B) {
+	canvas := NewCanvas(1000, 1000, app.FrameEvent{})
+	x, y := batchCoords()
+	fillcolor := color.NRGBA{128, 0, 0, 255}
+	for n := 0; n < b.N; n++ {
+		canvas.Context.Ops.Reset()
+		canvas.Rects(x, y, []float32{2}, []float32{3}, fillcolor)
+	}
+}
+
+func BenchmarkLine(b *testing.B) {
+	canvas := NewCanvas(1000, 1000, app.FrameEvent{})
+	x, y := batchCoords()
+	fillcolor := color.NRGBA{128, 0, 0, 255}
+	for n := 0; n < b.N; n++ {
+		canvas.Context.Ops.Reset()
+		for i := range x {
+			canvas.Line(x[i], y[i], x[i]+2, y[i]+3, 0.1, fillcolor)
+		}
+	}
+}
+
+func BenchmarkLines(b *testing.B) {
+	canvas := NewCanvas(1000, 1000, app.FrameEvent{})
+	x0, y0 := batchCoords()
+	x1, y1 := make([]float32, nbatch), make([]float32, nbatch)
+	for i := range x0 {
+		x1[i], y1[i] = x0[i]+2, y0[i]+3
+	}
+	fillcolor := color.NRGBA{128, 0, 0, 255}
+	for n := 0; n < b.N; n++ {
+		canvas.Context.Ops.Reset()
+		canvas.Lines(x0, y0, x1, y1, 0.1, fillcolor)
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		spec string
+		want color.NRGBA
+	}{
+		{"red", color.NRGBA{255, 0, 0, 255}},
+		{"RebeccaPurple", color.NRGBA{102, 51, 153, 255}},
+		{"transparent", color.NRGBA{0, 0, 0, 0}},
+		{"#f00", color.NRGBA{255, 0, 0, 255}},
+		{"#f008", color.NRGBA{255, 0, 0, 136}},
+		{"#aabbcc", color.NRGBA{170, 187, 204, 255}},
+		{"#aabbcc64", color.NRGBA{170, 187, 204, 100}},
+		{"#aa", color.NRGBA{170, 0, 0, 255}},
+		{"rgb(100)", color.NRGBA{100, 0, 0, 255}},
+		{"rgb(100,50)", color.NRGBA{100, 50, 0, 255}},
+		{"rgb(300,0,0)", color.NRGBA{255, 0, 0, 255}},
+		{"rgb(100,100,100,100)", color.NRGBA{100, 100, 100, 100}},
+		{"rgba(255, 0, 0, 0.5)", color.NRGBA{255, 0, 0, 128}},
+		{"rgb(255 0 0 / 50%)", color.NRGBA{255, 0, 0, 128}},
+		{"rgb(100% 0% 0%)", color.NRGBA{255, 0, 0, 255}},
+		{"hsl(120 100% 50%)", color.NRGBA{0, 255, 0, 255}},
+		{"hsla(120deg, 100%, 25%, 0.5)", color.NRGBA{0, 128, 0, 128}},
+		{"hsl(0.5turn 100% 50%)", color.NRGBA{0, 255, 255, 255}},
+		{"hwb(0 0% 0%)", color.NRGBA{255, 0, 0, 255}},
+		{"hwb(0 50% 50%)", color.NRGBA{128, 128, 128, 255}},
+		{"lab(54.29 80.8 69.89)", color.NRGBA{255, 0, 0, 255}},
+		{"lch(54.29 106.84 40.85)", color.NRGBA{255, 0, 0, 255}},
+		{"oklab(0.628 0.2249 0.1258)", color.NRGBA{255, 0, 0, 255}},
+		{"oklch(62.8% 0.2577 29.23)", color.NRGBA{255, 0, 0, 255}},
+		{"color(srgb 0.5 0.5 0.5)", color.NRGBA{128, 128, 128, 255}},
+		{"color(display-p3 1 0 0)", color.NRGBA{255, 0, 0, 255}},
+		{"hsv(0,70,50)", color.NRGBA{127, 38, 38, 255}},
+	}
+	for _, test := range tests {
+		got, err := ParseColor(test.spec)
+		if err != nil {
+			t.Errorf("ParseColor(%q): unexpected error %v", test.spec, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("ParseColor(%q) = %v, want %v", test.spec, got, test.want)
+		}
+	}
+	if got, want := ColorLookup("#aabb"), (color.NRGBA{170, 187, 0, 255}); got != want {
+		t.Errorf("ColorLookup(\"#aabb\") = %v, want %v", got, want)
+	}
+	if got, want := ColorLookup("#f008"), (color.NRGBA{240, 8, 0, 255}); got != want {
+		t.Errorf("ColorLookup(\"#f008\") = %v, want %v", got, want)
+	}
+	for _, spec := range []string{"", "nonsense", "#", "#error", "#12345", "rgb()", "hsv()", "rgb(1,2,3,4,5)", "rgb(1 2 3 /)", "rgba(1 2)", "lab(1 2)", "hsl(1 2% x)", "color(foo 1 2 3)"} {
+		if _, err := ParseColor(spec); err == nil {
+			t.Errorf("ParseColor(%q): expected an error", spec)
+		}
+	}
+}
+
+func TestContrast(t *testing.T) {
+	black := color.NRGBA{0, 0, 0, 255}
+	white := color.NRGBA{255, 255, 255, 255}
+	if r := Contrast(black, white); math.Abs(r-21) > 1e-9 {
+		t.Errorf("Contrast(black, white) = %v, want 21", r)
+	}
+	if r := Contrast(white, white); r != 1 {
+		t.Errorf("Contrast(white, white) = %v, want 1", r)
+	}
+	if c := TextColorFor(color.NRGBA{255, 255, 0, 255}); c != black {
+		t.Errorf("TextColorFor(yellow) = %v, want black", c)
+	}
+	if c := TextColorFor(color.NRGBA{0, 0, 128, 255}); c != white {
+		t.Errorf("TextColorFor(navy) = %v, want white", c)
+	}
+}
+
+func TestMix(t *testing.T) {
+	red := color.NRGBA{255, 0, 0, 255}
+	blue := color.NRGBA{0, 0, 255, 255}
+	if c := Mix(red, blue, 0); c != red {
+		t.Errorf("Mix(red, blue, 0) = %v, want %v", c, red)
+	}
+	if c := Mix(red, blue, 1); c != blue {
+		t.Errorf("Mix(red, blue, 1) = %v, want %v", c, blue)
+	}
+	if c := Lighten(color.NRGBA{128, 128, 128, 255}, 0.1); c.R <= 128 || c.R != c.G || c.G != c.B {
+		t.Errorf("Lighten(gray) = %v, want a lighter gray", c)
+	}
+	if c := HSV(120, 100, 100); c != (color.NRGBA{0, 255, 0, 255}) {
+		t.Errorf("HSV(120, 100, 100) = %v, want green", c)
+	}
+}
+
+func TestColorScale(t *testing.T) {
+	s := NewColorScale(Blues, 0, 100)
+	if c := s.Color(-10); c != Blues[0] {
+		t.Errorf("below domain: got %v, want %v", c, Blues[0])
+	}
+	if c := s.Color(100); c != Blues[len(Blues)-1] {
+		t.Errorf("top of domain: got %v, want %v", c, Blues[len(Blues)-1])
+	}
+	d := NewColorScale(RdBu, -1, 0, 10)
+	if c := d.Color(0); c != RdBu[5] {
+		t.Errorf("diverging midpoint: got %v, want %v", c, RdBu[5])
+	}
+	q := NewColorScale([]color.NRGBA{{255, 0, 0, 255}, {0, 0, 255, 255}}, 0, 1).Quantize(2)
+	if c := q.Color(0.4); c != q.Colors[0] {
+		t.Errorf("quantize 0.4: got %v, want %v", c, q.Colors[0])
+	}
+	if c := q.Color(0.6); c != q.Colors[1] {
+		t.Errorf("quantize 0.6: got %v, want %v", c, q.Colors[1])
+	}
+	red, white, blue := color.NRGBA{255, 0, 0, 255}, color.NRGBA{255, 255, 255, 255}, color.NRGBA{0, 0, 255, 255}
+	st := NewColorScale([]color.NRGBA{red, white, blue}, 0, 100).Stops(0, 0.2, 1)
+	for _, test := range []struct {
+		v    float64
+		want color.NRGBA
+	}{{0, red}, {20, white}, {10, Mix(red, white, 0.5)}, {60, Mix(white, blue, 0.5)}, {100, blue}} {
+		if c := st.Color(test.v); c != test.want {
+			t.Errorf("stops at %v: got %v, want %v", test.v, c, test.want)
+		}
+	}
+	if c := InterpolateAt([]color.NRGBA{red, white, blue}, []float64{0, 0.5, 0.5}, 0.6); c != blue {
+		t.Errorf("sharp stop: got %v, want %v", c, blue)
+	}
+	th := NewColorScale(Set1[:3]).Threshold(10, 20)
+	for _, test := range []struct {
+		v float64
+		i int
+	}{{5, 0}, {10, 1}, {15, 1}, {25, 2}} {
+		if c := th.Color(test.v); c != Set1[test.i] {
+			t.Errorf("threshold %v: got %v, want %v", test.v, c, Set1[test.i])
+		}
+	}
+	cat := NewCategoricalScale(Tableau10)
+	if c := cat.Color(12); c != Tableau10[2] {
+		t.Errorf("category 12: got %v, want %v", c, Tableau10[2])
+	}
+	for _, sc := range []*ColorScale{NewColorScale([]color.NRGBA{red, blue}, 0, 1), q, st, th, cat} {
+		if c := sc.Color(math.NaN()); c != (color.NRGBA{}) {
+			t.Errorf("NaN in mode %d: got %v, want transparent", sc.Mode, c)
+		}
+	}
+	gray := color.NRGBA{128, 128, 128, 255}
+	if c := (&ColorScale{Colors: []color.NRGBA{red, blue}, Domain: []float64{0, 1}, NaN: gray}).Color(math.NaN()); c != gray {
+		t.Errorf("NaN color: got %v, want %v", c, gray)
+	}
+	if c := Interpolate([]color.NRGBA{red, blue}, math.NaN()); c != red {
+		t.Errorf("interpolate NaN: got %v, want %v", c, red)
+	}
+	if c := InterpolateAt([]color.NRGBA{red, white, blue}, []float64{0, 0.2, 1}, math.NaN()); c != red {
+		t.Errorf("interpolate at NaN: got %v, want %v", c, red)
+	}
+}
+
+func TestGeometry(t *testing.T) {
+	square := R(0, 10, 10, 10).Polygon()
+	if a := square.Area(); a != 100 {
+		t.Errorf("Area: got %v, want 100", a)
+	}
+	if c := square.Centroid(); c != Pt(5, 5) {
+		t.Errorf("Centroid: got %v, want (5, 5)", c)
+	}
+	if !square.Contains(Pt(5, 5)) || square.Contains(Pt(15, 5)) {
+		t.Errorf("Contains: wrong result")
+	}
+	if b := square.Reverse().Bounds(); b != (Rect{Pt(0, 0), Pt(10, 10)}) {
+		t.Errorf("Bounds: got %v", b)
+	}
+	if d := Pt(5, 5).DistToSegment(Pt(0, 0), Pt(10, 0)); d != 5 {
+		t.Errorf("DistToSegment: got %v, want 5", d)
+	}
+	if p, ok := SegmentIntersection(Pt(0, 0), Pt(10, 10), Pt(0, 10), Pt(10, 0)); !ok || p != Pt(5, 5) {
+		t.Errorf("SegmentIntersection: got %v %v", p, ok)
+	}
+	if _, ok := SegmentIntersection(Pt(0, 0), Pt(1, 1), Pt(0, 10), Pt(10, 0)); ok {
+		t.Errorf("SegmentIntersection: segments should not cross")
+	}
+	if a := square.Offset(1).Area(); math.Abs(float64(a)-144) > 1e-3 {
+		t.Errorf("Offset(1) area: got %v, want 144", a)
+	}
+	if a := square.Reverse().Offset(-1).Area(); math.Abs(float64(a)-64) > 1e-3 {
+		t.Errorf("Offset(-1) area: got %v, want 64", a)
+	}
+	hull := ConvexHull([]Point{{0, 0}, {10, 0}, {5, 5}, {10, 10}, {0, 10}, {3, 7}})
+	if len(hull) != 4 || hull.Area() != 100 || hull.Clockwise() {
+		t.Errorf("ConvexHull: got %v", hull)
+	}
+}
+
+// area sums the signed areas of polygons, holes counting as negative
+func area(p []Polygon) float64 {
+	var a float64
+	for _, v := range p {
+		a += v.signedArea()
+	}
+	return a
+}
+
+func TestBoolean(t *testing.T) {
+	a := R(0, 10, 10, 10).Polygon()
+	b := R(5, 15, 10, 10).Polygon()
+	inner := R(2, 8, 4, 4).Polygon()
+	far := R(50, 60, 10, 10).Polygon()
+	tests := []struct {
+		name   string
+		result []Polygon
+		area   float64
+		n      int
+	}{
+		{"union", Union(a, b), 175, 1},
+		{"intersection", Intersection(a, b), 25, 1},
+		{"difference", Difference(a, b), 75, 1},
+		{"reversed difference", Difference(b.Reverse(), a), 75, 1},
+		{"hole", Difference(a, inner), 84, 2},
+		{"inside", Intersection(a, inner), 16, 1},
+		{"disjoint union", Union(a, far), 200, 2},
+		{"disjoint intersection", Intersection(a, far), 0, 0},
+		{"shared edge", Union(a, R(10, 10, 10, 10).Polygon()), 200, 1},
+		{"shared edge intersection", Intersection(a, R(10, 10, 10, 10).Polygon()), 0, 0},
+		{"same", Intersection(a, a), 100, 1},
+	}
+	for _, test := range tests {
+		if len(test.result) != test.n || math.Abs(area(test.result)-test.area) > 0.05 {
+			t.Errorf("%s: got %d polygons, area %v; want %d, %v", test.name, len(test.result), area(test.result), test.n, test.area)
+		}
+	}
+	// two interlocking U shapes enclose a hole
+	u1 := Polygon{{0, 0}, {30, 0}, {30, 20}, {20, 20}, {20, 10}, {10, 10}, {10, 20}, {0, 20}}
+	u2 := Polygon{{-2, 15}, {12, 15}, {12, 25}, {18, 25}, {18, 15}, {32, 15}, {32, 35}, {-2, 35}}
+	r := Union(u1, u2)
+	if len(r) != 2 || math.Abs(area(r)-1020) > 0.05 {
+		t.Errorf("interlocking union: got %d polygons, area %v; want 2, 1020", len(r), area(r))
+	}
+	hole := Polygon{{10, 10}, {10, 15}, {12, 15}, {12, 25}, {18, 25}, {18, 15}, {20, 15}, {20, 10}}
+	if len(r) == 2 && !samePolygon(r[0], hole) && !samePolygon(r[1], hole) {
+		t.Errorf("interlocking union: got %v, want a clockwise hole %v", r, hole)
+	}
+
+	// the vertices of the results are those of the polygons, and the points where their edges cross
+	vertices := []struct {
+		name   string
+		result []Polygon
+		want   []Polygon
+	}{
+		{"union", Union(a, b), []Polygon{{{0, 0}, {10, 0}, {10, 5}, {15, 5}, {15, 15}, {5, 15}, {5, 10}, {0, 10}}}},
+		{"intersection", Intersection(a, b), []Polygon{{{5, 5}, {10, 5}, {10, 10}, {5, 10}}}},
+		{"difference", Difference(a, b), []Polygon{{{0, 0}, {10, 0}, {10, 5}, {5, 5}, {5, 10}, {0, 10}}}},
+		{"difference along an edge", Difference(a, R(5, 10, 10, 10).Polygon()), []Polygon{{{0, 0}, {5, 0}, {5, 10}, {0, 10}}}},
+		{"union along an edge", Union(a, R(10, 10, 10, 10).Polygon()), []Polygon{{{0, 0}, {20, 0}, {20, 10}, {0, 10}}}},
+		{"small intersection", Intersection(R(0, 0.1, 0.1, 0.1).Polygon(), R(0.05, 0.1, 0.1, 0.1).Polygon()),
+			[]Polygon{{{0.05, 0}, {0.1, 0}, {0.1, 0.1}, {0.05, 0.1}}}},
+		{"touching at a corner", Union(a, R(10, 20, 10, 10).Polygon()), []Polygon{a, R(10, 20, 10, 10).Polygon()}},
+		{"hole along an edge", Difference(a, R(2, 10, 4, 4).Polygon()), []Polygon{{{0, 0}, {10, 0}, {10, 10}, {6, 10}, {6, 6}, {2, 6}, {2, 10}, {0, 10}}}},
+	}
+	for _, test := range vertices {
+		if !samePolygons(test.result, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, test.result, test.want)
+		}
+	}
+}
+
+// samePolygons reports whether the polygons in p and q are the same, in any order
+func samePolygons(p, q []Polygon) bool {
+	if len(p) != len(q) {
+		return false
+	}
+	used := make([]bool, len(q))
+	for _, a := range p {
+		found := false
+		for j, b := range q {
+			if !used[j] && samePolygon(a, b) {
+				used[j], found = true, true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// samePolygon reports whether p and q have the same vertices in the same order, from any start
+func samePolygon(p, q Polygon) bool {
+	if len(p) != len(q) {
+		return false
+	}
+	for start := range q {
+		same := true
+		for i := range p {
+			if p[i] != q[(start+i)%len(q)] {
+				same = false
+				break
+			}
+		}
+		if same {
+			return true
+		}
+	}
+	return false
+}
+
+// near reports whether two points are within 1e-3
+func near(p, q Point) bool {
+	return p.Dist(q) < 1e-3
+}
+
+func TestTransform(t *testing.T) {
+	var identity Transform
+	if p := identity.Apply(Pt(10, 20)); p != Pt(10, 20) {
+		t.Errorf("identity: got %v", p)
+	}
+	rot := identity.Rotate(50, 50, math.Pi/2)
+	if p := rot.Apply(Pt(60, 50)); !near(p, Pt(50, 40)) {
+		t.Errorf("rotate: got %v, want (50, 40)", p)
+	}
+	tr := identity.Scale(0, 0, 2).Translate(5, -5)
+	if p := tr.Apply(Pt(10, 10)); !near(p, Pt(25, 15)) {
+		t.Errorf("scale, translate: got %v, want (25, 15)", p)
+	}
+	if p := identity.Translate(5, -5).Mul(identity.Scale(0, 0, 2)).Apply(Pt(10, 10)); !near(p, Pt(25, 15)) {
+		t.Errorf("mul: got %v, want (25, 15)", p)
+	}
+	inv, ok := rot.Mul(tr).Invert()
+	if !ok {
+		t.Fatal("invert: not invertible")
+	}
+	if p := inv.Apply(rot.Mul(tr).Apply(Pt(3, 7))); !near(p, Pt(3, 7)) {
+		t.Errorf("invert: got %v, want (3, 7)", p)
+	}
+	if _, ok := identity.Scale(0, 0, 0).Invert(); ok {
+		t.Errorf("invert: zero scale should not be invertible")
+	}
+	parts := TransformParts{Translate: Pt(10, -3), Rotate: 0.5, Shear: 0.25, Scale: Pt(2, 3)}
+	got := parts.Transform().Decompose()
+	if !near(got.Translate, parts.Translate) || !near(got.Scale, parts.Scale) ||
+		math.Abs(float64(got.Rotate-parts.Rotate)) > 1e-4 || math.Abs(float64(got.Shear-parts.Shear)) > 1e-4 {
+		t.Errorf("decompose: got %+v, want %+v", got, parts)
+	}
+}
+
+func TestCurrentTransform(t *testing.T) {
+	c := NewCanvas(1000, 1000, app.FrameEvent{})
+	s1 := c.Rotate(50, 50, math.Pi/2)
+	s2 := c.PushTransform(Transform{}.Translate(10, 0))
+	want := Transform{}.Rotate(50, 50, math.Pi/2).Mul(Transform{}.Translate(10, 0))
+	if p, q := c.CurrentTransform().Apply(Pt(20, 30)), want.Apply(Pt(20, 30)); !near(p, q) {
+		t.Errorf("current: got %v, want %v", p, q)
+	}
+	c.EndTransform(s2)
+	c.EndTransform(s1)
+	if p := c.CurrentTransform().Apply(Pt(20, 30)); !near(p, Pt(20, 30)) {
+		t.Errorf("after end: got %v, want (20, 30)", p)
+	}
+	c.Push()
+	c.Scale(50, 50, 2)
+	c.Pop()
+	if len(c.applied) != 0 {
+		t.Errorf("after pop: %d transformations remain", len(c.applied))
+	}
+	// a transformation ended inside Push is not ended again by Pop
+	c.Push()
+	c.EndTransform(c.Rotate(50, 50, 1))
+	c.Pop()
+	if len(c.applied) != 0 || len(c.saved) != 0 {
+		t.Errorf("end inside push: %d transformations, %d saved states remain", len(c.applied), len(c.saved))
+	}
+	// as are transformations ended by the EndTransform function, or their Pop method
+	c.Push()
+	EndTransform(c.Scale(50, 50, 2))
+	c.Rotate(50, 50, 1).Pop()
+	if p := c.CurrentTransform().Apply(Pt(20, 30)); !near(p, Pt(20, 30)) {
+		t.Errorf("end function inside push: got %v, want (20, 30)", p)
+	}
+	c.Pop()
+	EndTransform(c.Translate(10, 0))
+	if p := c.CurrentTransform().Apply(Pt(20, 30)); !near(p, Pt(20, 30)) {
+		t.Errorf("end function: got %v, want (20, 30)", p)
+	}
+}
+
+func TestTiles(t *testing.T) {
+	c := NewCanvas(1000, 1000, app.FrameEvent{})
+	hex := c.HexTiles(0, 100, 100, 100, 5)
+	if len(hex) == 0 {
+		t.Fatal("HexTiles: no tiles")
+	}
+	if d := hex[0].Centroid().Dist(hex[1].Centroid()); math.Abs(float64(d)-5*math.Sqrt(3)) > 1e-3 {
+		t.Errorf("HexTiles: neighbors %v apart, want %v", d, 5*math.Sqrt(3))
+	}
+	var area float32
+	for _, p := range c.TriangleTiles(0, 100, 100, 100, 10) {
+		in := Intersection(p, R(0, 100, 100, 100).Polygon())
+		if len(in) == 0 {
+			t.Fatalf("TriangleTiles: tile %v is outside the area", p)
+		}
+		area += in[0].Area()
+	}
+	if math.Abs(float64(area)-10000) > 1 {
+		t.Errorf("TriangleTiles: covered area %v, want 10000", area)
+	}
+}
+
+func TestCorners(t *testing.T) {
+	tests := []struct {
+		r    Corners
+		w, h float32
+		want Corners
+	}{
+		{Radius(10), 100, 100, Radius(10)},
+		{Radius(10), 100, 10, Radius(5)},
+		{Corners{UpperLeft: 30, UpperRight: 10}, 20, 100, Corners{UpperLeft: 15, UpperRight: 5}},
+		{Corners{UpperLeft: 10, LowerLeft: 30}, 100, 20, Corners{UpperLeft: 5, LowerLeft: 15}},
+	}
+	for _, tc := range tests {
+		if got := tc.r.fit(tc.w, tc.h); got != tc.want {
+			t.Errorf("%v.fit(%v, %v) = %v, want %v", tc.r, tc.w, tc.h, got, tc.want)
+		}
+	}
+}
+
+func TestArrow(t *testing.T) {
+	b := cubic{f32.Pt(0, 0), f32.Pt(30, 0), f32.Pt(70, 0), f32.Pt(100, 0)}
+	l, r := b.split(0.5)
+	if l[3] != r[0] || l[3] != b.at(0.5) {
+		t.Errorf("split: %v and %v do not meet at %v", l, r, b.at(0.5))
+	}
+	if p := b.at(b.distance(10, false)); math.Abs(float64(p.X-90)) > 1e-3 {
+		t.Errorf("distance from end: got %v, want 90", p.X)
+	}
+	if p := b.at(b.distance(10, true)); math.Abs(float64(p.X-10)) > 1e-3 {
+		t.Errorf("distance from start: got %v, want 10", p.X)
+	}
+	c := NewCanvas(1000, 1000, app.FrameEvent{})
+	s, sdir, e, edir := c.connection(R(10, 60, 20, 20), R(60, 50, 20, 20), 1)
+	if s != f32.Pt(301, 500) || e != f32.Pt(599, 600) || sdir != f32.Pt(1, 0) || edir != f32.Pt(-1, 0) {
+		t.Errorf("connection: got %v %v %v %v", s, sdir, e, edir)
+	}
+}
+
+func TestPatternSpan(t *testing.T) {
+	f := patternFrame{u: f32.Pt(1, 0), v: f32.Pt(0, 1)}
+	s0, s1, t0, t1 := f.span(f32.Pt(15, 25), f32.Pt(45, 35), 10)
+	if s0 != 0 || s1 != 55 || t0 != 10 || t1 != 45 {
+		t.Errorf("span: got %v %v %v %v, want 0 55 10 45", s0, s1, t0, t1)
+	}
+	if p := f.at(3, 4); p != f32.Pt(3, 4) {
+		t.Errorf("at: got %v", p)
+	}
+}
+
+func TestGridAxes(t *testing.T) {
+	a := linearAxis(10, 20, 10, 2.5)
+	if len(a.major) != 3 || len(a.minor) != 6 || a.labels[2] != "30" {
+		t.Errorf("linearAxis: major %v minor %v labels %v", a.major, a.minor, a.labels)
+	}
+	l := logAxis(30, 3, true)
+	if len(l.major) != 4 || len(l.minor) != 24 || l.labels[3] != "1000" {
+		t.Errorf("logAxis: major %v minor %v labels %v", l.major, l.minor, l.labels)
+	}
+	if p := l.minor[0]; math.Abs(float64(p)-10*math.Log10(2)) > 1e-4 {
+		t.Errorf("logAxis: first minor line at %v, want %v", p, 10*math.Log10(2))
+	}
+}
+
+func TestTurtle(t *testing.T) {
+	l := LSystem{Axiom: "F", Rules: map[rune]string{'F': "F+F"}, Angle: 90}
+	if got := l.Expand(2); got != "F+F+F+F" {
+		t.Errorf("Expand: got %q, want %q", got, "F+F+F+F")
+	}
+	turtle := NewCanvas(1000, 1000, app.FrameEvent{}).NewTurtle(50, 50, 0)
+	turtle.PenUp()
+	turtle.Run("F+F[+F]F-f|F", 10, 90)
+	if x, y := turtle.Position(); math.Abs(float64(x-60)) > 1e-3 || math.Abs(float64(y-70)) > 1e-3 {
+		t.Errorf("Run: turtle at (%v, %v), want (60, 70)", x, y)
+	}
+	if h := turtle.Heading(); math.Abs(math.Mod(h+360, 360)-180) > 1e-6 {
+		t.Errorf("Run: heading %v, want 180", h)
+	}
+}
+
+func TestRand(t *testing.T) {
+	a, b := NewRand(42), NewRand(42)
+	for i := 0; i < 10; i++ {
+		if x, y := a.Range(-5, 5), b.Range(-5, 5); x != y || x < -5 || x >= 5 {
+			t.Fatalf("Range: got %v and %v from the same seed", x, y)
+		}
+	}
+	s := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	Shuffle(NewRand(7), s)
+	u := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	Shuffle(NewRand(7), u)
+	for i := range s {
+		if s[i] != u[i] {
+			t.Fatalf("Shuffle: %v and %v from the same seed", s, u)
+		}
+	}
+	if c := Choice(a, []string{}); c != "" {
+		t.Errorf("empty Choice: got %q", c)
+	}
+	n := NewNoise(1)
+	for i := 0; i < 1000; i++ {
+		x, y, z := float64(i)*0.137, float64(i)*0.071, float64(i)*0.029
+		for _, v := range []float64{n.Perlin1(x), n.Perlin2(x, y), n.Perlin3(x, y, z), n.Value2(x, y), n.Simplex2(x, y), n.Simplex3(x, y, z)} {
+			if v < -1 || v > 1 {
+				t.Fatalf("noise at (%v, %v, %v) = %v, outside -1 to 1", x, y, z, v)
+			}
+		}
+	}
+	if v := n.Perlin2(3, 4); v != 0 {
+		t.Errorf("Perlin2 at a lattice point: got %v, want 0", v)
+	}
+}
+
+func TestArcSweep(t *testing.T) {
+	tests := []struct{ a1, a2, want float64 }{
+		{0, math.Pi, math.Pi},
+		{math.Pi, 0, 2 * math.Pi},
+		{0, 2 * math.Pi, 2 * math.Pi},
+		{math.Pi / 2, -math.Pi / 2, 3 * math.Pi / 2},
+		{1, 1, 1},
+	}
+	for _, test := range tests {
+		if got := arcSweep(test.a1, test.a2); math.Abs(got-test.want) > 1e-9 {
+			t.Errorf("arcSweep(%v, %v): got %v, want %v", test.a1, test.a2, got, test.want)
+		}
+	}
+	canvas := NewCanvas(1000, 500, app.FrameEvent{})
+	canvas.ArcLine(50, 50, 20, 0, 2*math.Pi, 1, color.NRGBA{0, 0, 0, 255})
+	canvas.Annulus(50, 50, 10, 20, 0, math.Pi, color.NRGBA{0, 0, 0, 255})
+}
+
+func TestBatch(t *testing.T) {
+	if n := batchLen([]float32{1, 2}, []float32{3, 4}, []float32{1}, []float32{5, 6}); n != 2 {
+		t.Errorf("batchLen: got %d, want 2", n)
+	}
+	if n := batchLen([]float32{1, 2}, []float32{3}); n != -1 {
+		t.Errorf("batchLen with mismatched coordinates: got %d, want -1", n)
+	}
+	if n := batchLen([]float32{1, 2, 3}, []float32{3, 4, 5}, []float32{1, 2}); n != -1 {
+		t.Errorf("batchLen with mismatched sizes: got %d, want -1", n)
+	}
+	if v := batchValue([]float32{7}, 5); v != 7 {
+		t.Errorf("batchValue: got %v, want 7", v)
+	}
+}
+
+func TestLayers(t *testing.T) {
+	var layers Layers
+	bg, fg := layers.Layer("background"), layers.Layer("foreground")
+	if layers.Layer("background") != bg {
+		t.Error("Layer: did not return the existing layer")
+	}
+	if bg.Z >= fg.Z || !fg.Visible || fg.Opacity != 1 {
+		t.Errorf("Layer: new layer %+v is not visible and opaque above %+v", fg, bg)
+	}
+	bg.Z = 5
+	if order := layers.Order(); order[0] != fg || order[1] != bg {
+		t.Errorf("Order: got %s, %s; want foreground, background", order[0].Name, order[1].Name)
+	}
+	canvas := NewCanvas(1000, 1000, app.FrameEvent{})
+	ops := canvas.Context.Ops
+	canvas.DrawLayer(bg, func() {
+		if canvas.Context.Ops == ops {
+			t.Error("DrawLayer: drawing into the canvas, not the layer")
+		}
+		canvas.Circle(50, 50, 10, color.NRGBA{0, 0, 0, 255})
+	})
+	if canvas.Context.Ops != ops || !bg.Drawn() || fg.Drawn() {
+		t.Error("DrawLayer: canvas operations not restored, or layer not drawn")
+	}
+	canvas.Composite(&layers)
+	bg.Clear()
+	if bg.Drawn() {
+		t.Error("Clear: layer still drawn")
+	}
+}
+
+func TestScene(t *testing.T) {
+	dot := NewCircleShape(50, 50, 5)
+	dot.ID = "dot"
+	label := NewTextNode(50, 40, "label")
+	moving, still := NewGroup(dot, label), NewGroup(NewRectShape(20, 20, 10, 10), NewLineShape(0, 0, 100, 100))
+	scene := NewScene(moving, still)
+	canvas := NewCanvas(1000, 1000, app.FrameEvent{})
+	canvas.Render(scene)
+	if scene.Root.dirty || moving.dirty || still.dirty {
+		t.Fatal("Render: groups not drawn")
+	}
+	if scene.Find("dot") != dot || scene.Find("none") != nil {
+		t.Error("Find: did not find the node by ID")
+	}
+	dot.SetTransform(Transform{}.Translate(10, 0))
+	if !moving.dirty || !scene.Root.dirty || still.dirty {
+		t.Errorf("SetTransform: dirty groups: root %v, moving %v, still %v; want true, true, false", scene.Root.dirty, moving.dirty, still.dirty)
+	}
+	canvas.Render(scene)
+	still.SetVisible(false)
+	if still.dirty || !scene.Root.dirty {
+		t.Error("SetVisible: the group, not its parent, was marked to be redrawn")
+	}
+	canvas.Render(scene)
+	moving.SetStyle(DefaultStyle)
+	if !moving.dirty || still.dirty {
+		t.Error("SetStyle: the group was not marked to be redrawn")
+	}
+	canvas.Render(scene)
+	still.Add(label)
+	if label.Parent() != still || len(moving.Children()) != 1 {
+		t.Error("Add: node not moved between groups")
+	}
+	canvas = NewCanvas(800, 600, app.FrameEvent{})
+	canvas.Render(scene) // the hidden group is left to be redrawn when it is shown
+	if scene.width != 800 || moving.dirty || !still.dirty {
+		t.Error("Render: visible groups not redrawn for a new canvas size")
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	dir := t.TempDir()
+	colors := []color.NRGBA{{255, 0, 0, 255}, {0, 255, 0, 255}, {0, 0, 255, 255}}
+	record := func(name string) string {
+		path := filepath.Join(dir, name)
+		r, err := NewRecorder(path, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, c := range colors {
+			frame := image.NewNRGBA(image.Rect(0, 0, 4, 3))
+			draw.Draw(frame, frame.Rect, image.NewUniform(c), image.Point{}, draw.Src)
+			if err := r.Add(frame); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := r.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	path := record("frame-%d.png")
+	for i := range colors {
+		if _, err := os.Stat(fmt.Sprintf(path, i)); err != nil {
+			t.Error(err)
+		}
+	}
+
+	f, err := os.Open(record("loop.gif"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := gif.DecodeAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Image) != 3 || g.Delay[0] != 10 {
+		t.Errorf("GIF: %d frames with delay %d, want 3 with delay 10", len(g.Image), g.Delay[0])
+	}
+
+	data, err := os.ReadFile(record("loop.apng"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(data, []byte("acTL")) || bytes.Count(data, []byte("fdAT")) != 2 {
+		t.Error("APNG: missing animation chunks")
+	}
+	first, err := png.Decode(bytes.NewReader(data)) // decoders without APNG support show the first frame
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := color.NRGBAModel.Convert(first.At(3, 2)); got != colors[0] {
+		t.Errorf("APNG: first frame %v, want %v", got, colors[0])
+	}
+
+	if _, err := NewRecorder("loop.jpg", 10); err == nil {
+		t.Error("NewRecorder: no error for an unknown format")
+	}
+}
+
+func TestCaptureKey(t *testing.T) {
+	var c Capture
+	if c.Key(key.Event{Name: "S", State: key.Press}) {
+		t.Error("Key: S without Ctrl handled")
+	}
+	if !c.Key(key.Event{Name: "S", Modifiers: key.ModCtrl, State: key.Press}) || !c.snap {
+		t.Error("Key: Ctrl+S not handled")
+	}
+	if !c.Key(key.Event{Name: "R", Modifiers: key.ModCtrl, State: key.Press}) || !c.start {
+		t.Error("Key: Ctrl+R not handled")
+	}
+}
+
+const quote = "If there is no struggle, there is no progress. Those who profess to favor freedom, and yet depreciate agitation, want crops without plowing up the ground."
+
+func TestTextBox(t *testing.T) {
+	canvas := NewCanvas(300, 300, app.FrameEvent{})
+	box := canvas.setText(100, 200, 12, quote, TextBoxStyle{})
+	if box.overflow || len(box.lines) < 3 {
+		t.Fatalf("setText: %d lines, overflow %v", len(box.lines), box.overflow)
+	}
+	for _, line := range box.lines {
+		if line.width > 100 {
+			t.Errorf("setText: line %q is %.1f wide, in a box 100 wide", strings.Join(line.words, " "), line.width)
+		}
+	}
+	box = canvas.setText(100, 100, 12, quote, TextBoxStyle{MaxLines: 2})
+	last := box.lines[len(box.lines)-1].words
+	if len(box.lines) != 2 || !box.overflow || !strings.HasSuffix(last[len(last)-1], "…") {
+		t.Errorf("setText with MaxLines: %d lines, overflow %v, ending %q", len(box.lines), box.overflow, last)
+	}
+	box = canvas.setText(100, 40, 12, quote, TextBoxStyle{MinSize: 4})
+	if box.overflow || box.size >= 12 || box.size < 4 {
+		t.Errorf("setText with MinSize: size %.1f, overflow %v", box.size, box.overflow)
+	}
+	box = canvas.setText(30, 100, 12, "incomprehensibilities", TextBoxStyle{})
+	if len(box.lines) < 2 || box.overflow {
+		t.Errorf("setText: long word in %d lines, overflow %v", len(box.lines), box.overflow)
+	}
+}
+
+func TestTextPath(t *testing.T) {
+	canvas := NewCanvas(200, 100, app.FrameEvent{})
+	line := canvas.LinePath(0, 50, 100, 50)
+	if line.Length() != 200 {
+		t.Errorf("LinePath: length %v, want 200", line.Length())
+	}
+	for _, test := range []struct {
+		d    float32
+		want f32.Point
+	}{{100, f32.Pt(100, 50)}, {-10, f32.Pt(-10, 50)}, {210, f32.Pt(210, 50)}} {
+		if p, dir := line.at(test.d); p.Sub(test.want).X > 0.01 || p.Sub(test.want).X < -0.01 || p.Y != 50 || dir != 0 {
+			t.Errorf("at(%v) = %v, %v, want %v, 0", test.d, p, dir, test.want)
+		}
+	}
+	circle := canvas.CirclePath(50, 50, 10, math.Pi/2) // radius 20 pixels, beginning at the top, clockwise
+	if l := circle.Length(); math.Abs(float64(l)-40*math.Pi) > 0.5 {
+		t.Errorf("CirclePath: length %v, want %v", l, 40*math.Pi)
+	}
+	if p, dir := circle.at(0); math.Abs(float64(p.X-100)) > 0.01 || math.Abs(float64(p.Y-30)) > 0.01 || math.Abs(float64(dir)) > 0.15 {
+		t.Errorf("CirclePath: begins at %v, direction %v, want (100, 30), 0", p, dir)
+	}
+	var chars []string
+	clusters := canvas.textClusters("héllo", 20)
+	for _, cl := range clusters {
+		chars = append(chars, cl.s)
+	}
+	if strings.Join(chars, "") != "héllo" || clusters[0].advance <= 0 {
+		t.Errorf("textClusters: %q, %v", chars, clusters)
+	}
+	// right to left text is set from its end, at the left
+	if clusters := canvas.textClusters("שלום", 20); len(clusters) != 4 || clusters[0].s != "ם" || clusters[0].i != 3 {
+		t.Errorf("textClusters: right to left %v", clusters)
+	}
+}
+
+func TestDirection(t *testing.T) {
+	canvas := NewCanvas(300, 300, app.FrameEvent{})
+	for _, test := range []struct {
+		s   string
+		rtl bool
+	}{
+		{"hello", false},
+		{"שלום", true},
+		{"مرحبا", true},
+		{"123 שלום", true},
+		{"(hello) שלום", false},
+		{"42", false},
+	} {
+		if rtl := canvas.rtl(test.s); rtl != test.rtl {
+			t.Errorf("rtl(%q) = %v, want %v", test.s, rtl, test.rtl)
+		}
+	}
+	canvas.Direction = RTL
+	if !canvas.rtl("hello") {
+		t.Error("rtl: RTL canvas sets left to right text left to right")
+	}
+	canvas.Direction = LTR
+	if canvas.rtl("שלום") {
+		t.Error("rtl: LTR canvas sets right to left text right to left")
+	}
+	for _, test := range []struct {
+		words []string
+		rtl   bool
+		want  []int
+	}{
+		{[]string{"a", "b", "c"}, false, []int{0, 1, 2}},
+		{[]string{"א", "ב", "ג"}, true, []int{2, 1, 0}},
+		{[]string{"א", "b", "c", "ד"}, true, []int{3, 1, 2, 0}},
+		{[]string{"a", "ב", "ג", "d"}, false, []int{0, 2, 1, 3}},
+	} {
+		if got := visualOrder(test.words, test.rtl); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("visualOrder(%q, %v) = %v, want %v", test.words, test.rtl, got, test.want)
+		}
+	}
+	canvas.Direction = DirectionAuto
+	box := canvas.setText(100, 100, 12, "שלום עולם\nhello world", TextBoxStyle{})
+	if len(box.lines) != 2 || !box.lines[0].rtl || box.lines[1].rtl {
+		t.Errorf("setText: paragraph directions %v", box.lines)
+	}
+}
+
+// checkGolden draws on a white canvas, and compares the drawing with its golden image
+func checkGolden(t *testing.T, name string, draw func(c *Canvas)) {
+	t.Helper()
+	const width, height = 300, 300
+	canvas := NewCanvas(width, height, app.FrameEvent{})
+	canvas.Background(color.NRGBA{255, 255, 255, 255})
+	draw(canvas)
+	golden.Check(t, name, width, height, canvas.Context.Ops)
+}
+
+func TestGolden(t *testing.T) {
+	fill := color.NRGBA{70, 130, 180, 200}
+	stroke := color.NRGBA{128, 0, 0, 255}
+	black := color.NRGBA{0, 0, 0, 255}
+	checker := image.NewNRGBA(image.Rect(0, 0, 8, 8))
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			if (x/2+y/2)%2 == 0 {
+				checker.Set(x, y, stroke)
+			}
+		}
+	}
+	tests := []struct {
+		name string
+		draw func(c *Canvas)
+	}{
+		{"lines", func(c *Canvas) {
+			c.Line(10, 10, 90, 90, 1, stroke)
+			c.HLine(10, 50, 80, 2, fill)
+			c.VLine(50, 10, 80, 2, fill)
+			c.Lines([]float32{10, 10}, []float32{80, 20}, []float32{90, 90}, []float32{80, 20}, 0.5, black)
+		}},
+		{"shapes", func(c *Canvas) {
+			c.Circle(25, 75, 15, fill)
+			c.Ellipse(75, 75, 20, 10, fill)
+			c.Rect(25, 25, 30, 20, fill)
+			c.Square(75, 25, 20, fill)
+			c.CornerRect(5, 55, 10, 10, stroke)
+		}},
+		{"polygon", func(c *Canvas) {
+			c.Polygon([]float32{10, 50, 90, 70, 30}, []float32{10, 90, 10, 50, 50}, fill)
+		}},
+		{"curves", func(c *Canvas) {
+			c.Curve(10, 10, 50, 90, 90, 10, fill)
+			c.CubeCurve(10, 50, 30, 100, 70, 0, 90, 50, fill)
+			c.StrokedCurve(10, 90, 50, 50, 90, 90, 1, stroke)
+		}},
+		{"arcs", func(c *Canvas) {
+			c.Arc(30, 70, 20, 0, math.Pi/2, fill)
+			c.ArcLine(70, 70, 20, 0, 3*math.Pi/2, 1, stroke)
+			c.EllipticalArcLine(30, 30, 20, 10, 0, math.Pi, 1, stroke)
+			c.Annulus(70, 30, 10, 20, math.Pi/4, 2*math.Pi, fill)
+		}},
+		{"text", func(c *Canvas) {
+			c.Text(50, 85, 6, "Text", black)
+			c.CText(50, 70, 6, "CText", black)
+			c.EText(50, 55, 6, "EText", black)
+			c.TextWrap(10, 40, 4, 80, "TextWrap wraps text within a width", stroke)
+		}},
+		{"textbox", func(c *Canvas) {
+			light := color.NRGBA{230, 230, 230, 255}
+			boxes := []struct {
+				x, y  float32
+				style TextBoxStyle
+			}{
+				{5, 95, TextBoxStyle{Align: AlignStart, VAlign: AlignTop}},
+				{55, 95, TextBoxStyle{Align: AlignCenter, VAlign: AlignMiddle}},
+				{5, 45, TextBoxStyle{Align: AlignJustify, VAlign: AlignBottom, MinSize: 2}},
+				{55, 45, TextBoxStyle{Align: AlignEnd, MaxLines: 4}},
+			}
+			for _, b := range boxes {
+				c.Rect(b.x+20, b.y-20, 40, 40, light)
+				c.TextBox(b.x, b.y, 40, 40, 4, quote, black, b.style)
+			}
+		}},
+		{"textpath", func(c *Canvas) {
+			c.ArcLine(50, 65, 20, 0, 2*math.Pi, 0.3, fill)
+			c.TextOnPath(c.ArcPath(50, 65, 22, math.Pi, 0), 5, "over the top", black, TextPathStyle{Offset: 34.5, Align: AlignCenter})
+			c.TextOnPath(c.ArcPath(50, 65, 20, math.Pi, 2*math.Pi), 5, "underneath", stroke, TextPathStyle{Offset: 31.4, Align: AlignCenter, Shift: -4})
+			c.TextOnPath(c.QuadPath(5, 20, 50, 45, 95, 20), 5, "a curve, justified", black, TextPathStyle{Align: AlignJustify})
+			c.TextOnPath(c.LinePath(10, 8, 90, 8), 5, "per glyph", stroke, TextPathStyle{Spacing: 2, Glyph: func(i int) (float32, float32) {
+				return float32(i) * 0.2, 1 + float32(i%3)*0.25
+			}})
+		}},
+		{"vtext", func(c *Canvas) {
+			c.Line(0, 50, 100, 50, 0.2, fill)
+			for i, orient := range []TextOrientation{RotateUp, RotateDown, Stacked} {
+				x := float32(10 + 33*i)
+				c.Line(x, 0, x, 100, 0.2, fill)
+				c.VText(x, 50, 5, "Start", black, orient)
+				c.VTextMid(x+10, 50, 5, "Mid", stroke, orient)
+				c.VTextEnd(x+20, 50, 5, "End", black, orient)
+			}
+		}},
+		{"image", func(c *Canvas) {
+			c.Img(checker, 50, 50, 8, 8, 500)
+		}},
+		{"rounded", func(c *Canvas) {
+			c.CenterRoundedRect(50, 70, 60, 30, Radius(5), fill)
+			c.StrokedCenterRoundedRect(50, 25, 60, 30, Corners{UpperLeft: 10, LowerRight: 10}, 1, stroke)
+		}},
+		{"arrows", func(c *Canvas) {
+			c.Arrow(10, 80, 90, 80, 1, ArrowStyle{End: FilledHead}, black)
+			c.Arrow(10, 60, 90, 60, 1, ArrowStyle{Start: CircleHead, End: OpenHead}, black)
+			c.CurveArrow(10, 20, 50, 50, 90, 20, 1, ArrowStyle{Start: BarHead, End: DiamondHead}, stroke)
+		}},
+		{"patterns", func(c *Canvas) {
+			c.PatternRect(25, 75, 40, 40, Pattern{Kind: Stripes, Color: stroke, Angle: math.Pi / 4})
+			c.PatternCircle(75, 75, 20, Pattern{Kind: Dots, Color: fill, Spacing: 4})
+			c.PatternRect(25, 25, 40, 40, Pattern{Kind: Crosshatch, Color: black, Spacing: 5})
+			c.PatternRect(75, 25, 40, 40, Pattern{Kind: Checker, Color: fill, Spacing: 5})
+		}},
+		{"grids", func(c *Canvas) {
+			style := GridStyle{Major: 20, Minor: 5, MajorColor: black, MinorColor: fill, LabelSize: 3, LabelColor: stroke}
+			c.LabeledGrid(5, 5, 90, 90, style)
+		}},
+		{"batch", func(c *Canvas) {
+			x, y := make([]float32, 100), make([]float32, 100)
+			for i := range x {
+				x[i], y[i] = float32(i%10)*10+5, float32(i/10)*10+5
+			}
+			c.Circles(x, y, []float32{3}, fill)
+			c.Squares(x, y, []float32{2}, stroke)
+		}},
+		{"transforms", func(c *Canvas) {
+			stack := c.Rotate(50, 50, math.Pi/6)
+			c.Rect(50, 50, 60, 20, fill)
+			c.EndTransform(stack)
+			stack = c.Scale(50, 50, 0.5)
+			c.Rect(50, 50, 60, 20, stroke)
+			c.EndTransform(stack)
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			checkGolden(t, test.name, test.draw)
+		})
+	}
+}
@@ -7,7 +7,8 @@ toolchain go1.22.2
 require (
 	gioui.org v0.8.0
 	github.com/ajstarks/deck v0.0.0-20230623153652-ebe7b794a4b1
-	github.com/disintegration/gift v1.2.1
+	golang.org/x/image v0.18.0
+	golang.org/x/text v0.16.0
 )
 
 require (
@@ -15,7 +16,5 @@ require (
 	github.com/go-text/typesetting v0.2.1 // indirect
 	golang.org/x/exp v0.0.0-20240707233637-46b078467d37 // indirect
 	golang.org/x/exp/shiny v0.0.0-20240707233637-46b078467d37 // indirect
-	golang.org/x/image v0.18.0 // indirect
 	golang.org/x/sys v0.22.0 // indirect
-	golang.org/x/text v0.16.0 // indirect
 )
@@ -0,0 +1,154 @@
+// Package golden compares drawings with stored reference ("golden") images, for regression tests.
+//
+// Drawings are rendered offscreen with the GPU (without a display, Mesa's surfaceless EGL
+// platform is used). Golden images are PNG files in the testdata directory of the package
+// under test; run the tests with -update to write them. When a drawing does not match,
+// the rendered image and an image marking the differences are written to testdata/failed.
+//
+// Mesa's software renderer draws rotated, scaled and sheared text lighter than a window does,
+// and may leave out some of it when there are many distinct transformations; golden images
+// of such text record that rendering, so they still catch changes to it.
+package golden
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gioui.org/op"
+	"github.com/ajstarks/giocanvas/internal/offscreen"
+)
+
+// Update makes Check write the golden images, rather than compare with them
+var Update = flag.Bool("update", false, "update the golden images")
+
+// Dir is the directory of the golden images
+var Dir = "testdata"
+
+// Tolerances: Threshold is the perceptual color difference (0-1) at which pixels differ,
+// and MaxDiff the fraction of the pixels that may differ.
+var (
+	Threshold = 0.1
+	MaxDiff   = 0.001
+)
+
+// Render draws ops offscreen, returning the image
+func Render(width, height int, ops *op.Ops) (*image.RGBA, error) {
+	return offscreen.Render(width, height, ops)
+}
+
+// yiq returns the YIQ components of c, blended over white
+func yiq(c color.Color) (y, i, q float64) {
+	r, g, b, a := c.RGBA()
+	white := float64(0xffff - a)
+	fr, fg, fb := (float64(r)+white)/257, (float64(g)+white)/257, (float64(b)+white)/257
+	y = fr*0.29889531 + fg*0.58662247 + fb*0.11448223
+	i = fr*0.59597799 - fg*0.27417610 - fb*0.32180189
+	q = fr*0.21147017 - fg*0.52261711 + fb*0.31114694
+	return y, i, q
+}
+
+// Delta returns the perceptual difference between two colors, from 0 (the same) to 1 (black and white),
+// using the weighted YIQ distance of Kotsarenko and Ramos, as in pixelmatch
+func Delta(a, b color.Color) float64 {
+	const max = 35215 // the distance between black and white
+	y1, i1, q1 := yiq(a)
+	y2, i2, q2 := yiq(b)
+	dy, di, dq := y1-y2, i1-i2, q1-q2
+	return (0.5053*dy*dy + 0.299*di*di + 0.1957*dq*dq) / max
+}
+
+// Compare returns the number of pixels of got that differ from want, and an image of the differences:
+// a faded copy of want, with the differing pixels in red
+func Compare(want, got image.Image) (int, *image.RGBA) {
+	b := want.Bounds()
+	diff := image.NewRGBA(b)
+	n := 0
+	t := Threshold * Threshold
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			w := want.At(x, y)
+			if Delta(w, got.At(x, y)) > t {
+				diff.Set(x, y, color.RGBA{255, 0, 0, 255})
+				n++
+				continue
+			}
+			luma, _, _ := yiq(w)
+			v := uint8(255 - (255-luma)/10)
+			diff.Set(x, y, color.RGBA{v, v, v, 255})
+		}
+	}
+	return n, diff
+}
+
+// Check renders ops, and compares the result with the golden image name.png.
+// The test is skipped if drawing offscreen is not possible.
+func Check(t testing.TB, name string, width, height int, ops *op.Ops) {
+	t.Helper()
+	got, err := Render(width, height, ops)
+	if err != nil {
+		t.Skipf("%s: cannot render offscreen: %v", name, err)
+	}
+	path := filepath.Join(Dir, name+".png")
+	if *Update {
+		if err := writePNG(path, got); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := readPNG(path)
+	if err != nil {
+		t.Fatalf("%s: %v (run the tests with -update to make the golden image)", name, err)
+	}
+	if want.Bounds() != got.Bounds() {
+		t.Fatalf("%s: size %v, golden image size %v", name, got.Bounds().Size(), want.Bounds().Size())
+	}
+	n, diff := Compare(want, got)
+	if float64(n) <= MaxDiff*float64(width*height) {
+		return
+	}
+	failed := filepath.Join(Dir, "failed")
+	gotpath, diffpath := filepath.Join(failed, name+".png"), filepath.Join(failed, name+".diff.png")
+	if err := writePNG(gotpath, got); err != nil {
+		t.Error(err)
+	}
+	if err := writePNG(diffpath, diff); err != nil {
+		t.Error(err)
+	}
+	t.Errorf("%s: %d pixels differ from the golden image; see %s and %s", name, n, gotpath, diffpath)
+}
+
+// readPNG reads an image from a PNG file
+func readPNG(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return img, nil
+}
+
+// writePNG writes an image to a PNG file, making its directory if needed
+func writePNG(path string, img image.Image) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -14,8 +14,8 @@ import (
 
 func main() {
 	var cw, ch int
-	var x1, x2, y1, y2, xincr, yincr, lw float64
-	var color, bgcolor string
+	var x1, x2, y1, y2, xincr, yincr, minor, lw float64
+	var color, bgcolor, gridtype string
 	flag.IntVar(&cw, "width", 1000, "canvas width")
 	flag.IntVar(&ch, "height", 1000, "canvas height")
 	flag.Float64Var(&x1, "x1", 0, "x begin")
@@ -24,7 +24,9 @@ func main() {
 	flag.Float64Var(&y2, "y2", 100, "y end")
 	flag.Float64Var(&xincr, "xincr", 10, "x increment")
 	flag.Float64Var(&yincr, "yincr", 10, "y increment")
+	flag.Float64Var(&minor, "minor", 0, "minor increment (0 for none)")
 	flag.Float64Var(&lw, "lw", 0.1, "line width")
+	flag.StringVar(&gridtype, "type", "square", "grid type (square, labeled, polar, log, iso, dot)")
 	flag.StringVar(&color, "color", "black", "grid color")
 	flag.StringVar(&bgcolor, "bgcolor", "white", "Background color")
 	flag.Parse()
@@ -35,7 +37,13 @@ func main() {
 	go func() {
 		w := &app.Window{}
 		w.Option(app.Title("grid"), app.Size(unit.Dp(width), unit.Dp(height)))
-		if err := grid(w, float32(x1), float32(x2), float32(y1), float32(y2), float32(xincr), float32(yincr), float32(lw), bgcolor, color); err != nil {
+		var err error
+		if gridtype == "square" {
+			err = grid(w, float32(x1), float32(x2), float32(y1), float32(y2), float32(xincr), float32(yincr), float32(lw), bgcolor, color)
+		} else {
+			err = styledgrid(w, gridtype, float32(x1), float32(x2), float32(y1), float32(y2), float32(xincr), float32(minor), float32(lw), bgcolor, color)
+		}
+		if err != nil {
 			io.WriteString(os.Stderr, "Cannot create the window\n")
 			os.Exit(1)
 		}
@@ -74,3 +82,47 @@ func grid(w *app.Window, x1, x2, y1, y2, xincr, yincr, lw float32, bgcolor, grid
 		}
 	}
 }
+
+// styledgrid shows one of the grid variants, with major lines at incr, and minor lines at minor
+func styledgrid(w *app.Window, gridtype string, x1, x2, y1, y2, incr, minor, lw float32, bgcolor, gridcolor string) error {
+	bcolor := giocanvas.ColorLookup(bgcolor)
+	style := giocanvas.GridStyle{
+		Major:      incr,
+		Minor:      minor,
+		MajorSize:  lw,
+		MinorSize:  lw / 2,
+		MajorColor: giocanvas.ColorLookup(gridcolor),
+		MinorColor: giocanvas.ColorLookup(gridcolor),
+		LabelSize:  incr / 5,
+		LabelColor: giocanvas.ColorLookup(gridcolor),
+	}
+	style.MinorColor.A = 100
+	style.LabelColor.A = 150
+	for {
+		ev := w.Event()
+		switch e := ev.(type) {
+		case app.DestroyEvent:
+			return e.Err
+		case app.FrameEvent:
+			canvas := giocanvas.NewCanvas(float32(e.Size.X), float32(e.Size.Y), app.FrameEvent{})
+			canvas.Background(bcolor)
+			switch gridtype {
+			case "labeled":
+				canvas.LabeledGrid(x1, y1, x2-x1, y2-y1, style)
+			case "polar":
+				r := (x2 - x1) / 2
+				style.LabelSize = 1.5
+				canvas.PolarGrid(x1+r, (y1+y2)/2, r*0.85, 12, 3, style)
+			case "log":
+				style.LabelSize = 1.5
+				canvas.LogGrid(x1, y1, x2-x1, y2-y1, 4, 4, style)
+			case "iso":
+				canvas.IsometricGrid(x1, y1, x2-x1, y2-y1, style)
+			case "dot":
+				style.MajorSize, style.MinorSize = lw*3, lw*1.5
+				canvas.DotGrid(x1, y1, x2-x1, y2-y1, style)
+			}
+			e.Frame(canvas.Context.Ops)
+		}
+	}
+}
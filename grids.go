@@ -0,0 +1,265 @@
+package giocanvas
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+
+	"gioui.org/f32"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+)
+
+// Grid variants, using percentage-based coordinates. Rectangular grids have their
+// lower left corner at (x, y), like Grid. Minor lines are drawn first, and are
+// omitted where they fall on major lines.
+
+// GridStyle describes the lines and labels of a grid
+type GridStyle struct {
+	Major, Minor           float32     // intervals between major and minor lines; minor lines are omitted if zero
+	MajorSize, MinorSize   float32     // line widths, or dot radii for dot grids (defaults 0.1 and 0.05)
+	MajorColor, MinorColor color.NRGBA // line colors
+	LabelSize              float32     // size of the labels on major lines; no labels if zero
+	LabelColor             color.NRGBA // label color
+}
+
+// sizes returns the major and minor line widths, with defaults
+func (s GridStyle) sizes() (float32, float32) {
+	major, minor := s.MajorSize, s.MinorSize
+	if major <= 0 {
+		major = 0.1
+	}
+	if minor <= 0 {
+		minor = 0.05
+	}
+	return major, minor
+}
+
+// ticks returns the offsets from 0 through length at interval
+func ticks(length, interval float32) []float32 {
+	if interval <= 0 || length < 0 {
+		return nil
+	}
+	n := int(length/interval + 1e-3)
+	t := make([]float32, n+1)
+	for i := range t {
+		t[i] = float32(i) * interval
+	}
+	return t
+}
+
+// onMajor reports whether offset v falls on a major interval
+func onMajor(v, major float32) bool {
+	if major <= 0 {
+		return false
+	}
+	r := math.Abs(math.Mod(float64(v), float64(major)))
+	return r < 1e-3*float64(major) || float64(major)-r < 1e-3*float64(major)
+}
+
+// gridlabel formats a grid value
+func gridlabel(v float64) string {
+	return strconv.FormatFloat(v, 'g', 4, 64)
+}
+
+// axis describes the lines across one axis of a rectangular grid
+type axis struct {
+	major, minor []float32 // offsets of the lines
+	labels       []string  // labels of the major lines
+}
+
+// linearAxis makes an axis with evenly spaced lines from start over length
+func linearAxis(start, length, major, minor float32) axis {
+	var a axis
+	for _, v := range ticks(length, minor) {
+		if !onMajor(v, major) {
+			a.minor = append(a.minor, v)
+		}
+	}
+	for _, v := range ticks(length, major) {
+		a.major = append(a.major, v)
+		a.labels = append(a.labels, gridlabel(float64(start+v)))
+	}
+	return a
+}
+
+// logAxis makes an axis with major lines at powers of ten, and if minor is set,
+// minor lines at their multiples (2-9)
+func logAxis(length float32, decades int, minor bool) axis {
+	var a axis
+	d := length / float32(decades)
+	for i := 0; i <= decades; i++ {
+		a.major = append(a.major, float32(i)*d)
+		a.labels = append(a.labels, gridlabel(math.Pow10(i)))
+		if minor && i < decades {
+			for k := 2; k < 10; k++ {
+				a.minor = append(a.minor, (float32(i)+float32(math.Log10(float64(k))))*d)
+			}
+		}
+	}
+	return a
+}
+
+// rectgrid draws the lines and labels of a rectangular grid
+func (c *Canvas) rectgrid(x, y, w, h float32, xa, ya axis, style GridStyle) {
+	majorsize, minorsize := style.sizes()
+	for _, v := range xa.minor {
+		c.Line(x+v, y, x+v, y+h, minorsize, style.MinorColor)
+	}
+	for _, v := range ya.minor {
+		c.Line(x, y+v, x+w, y+v, minorsize, style.MinorColor)
+	}
+	for _, v := range xa.major {
+		c.Line(x+v, y, x+v, y+h, majorsize, style.MajorColor)
+	}
+	for _, v := range ya.major {
+		c.Line(x, y+v, x+w, y+v, majorsize, style.MajorColor)
+	}
+	ls := style.LabelSize
+	if ls <= 0 {
+		return
+	}
+	// labels are placed inside the left and bottom edges, skipping the corners
+	for i, v := range xa.major {
+		if v > 0 && v < w {
+			c.CText(x+v, y+ls, ls, xa.labels[i], style.LabelColor)
+		}
+	}
+	for i, v := range ya.major {
+		if v > 0 && v < h {
+			c.CText(x+ls, y+v-(ls/2), ls, ya.labels[i], style.LabelColor)
+		}
+	}
+}
+
+// LabeledGrid makes a grid with major and minor lines, labeling the major lines with their coordinates
+func (c *Canvas) LabeledGrid(x, y, w, h float32, style GridStyle) {
+	c.rectgrid(x, y, w, h, linearAxis(x, w, style.Major, style.Minor), linearAxis(y, h, style.Major, style.Minor), style)
+}
+
+// LogGrid makes a grid with logarithmic axes spanning a number of decades.
+// Major lines are at powers of ten, and minor lines, if style.Minor is non-zero, at their multiples.
+// An axis with zero decades is linear, using the style's intervals.
+func (c *Canvas) LogGrid(x, y, w, h float32, xdecades, ydecades int, style GridStyle) {
+	xa, ya := linearAxis(x, w, style.Major, style.Minor), linearAxis(y, h, style.Major, style.Minor)
+	if xdecades > 0 {
+		xa = logAxis(w, xdecades, style.Minor > 0)
+	}
+	if ydecades > 0 {
+		ya = logAxis(h, ydecades, style.Minor > 0)
+	}
+	c.rectgrid(x, y, w, h, xa, ya, style)
+}
+
+// DotGrid makes a grid of dots at the intersections of major and minor lines
+func (c *Canvas) DotGrid(x, y, w, h float32, style GridStyle) {
+	majorsize, minorsize := style.sizes()
+	for _, v := range ticks(h, style.Minor) {
+		for _, u := range ticks(w, style.Minor) {
+			if !onMajor(u, style.Major) || !onMajor(v, style.Major) {
+				c.Circle(x+u, y+v, minorsize, style.MinorColor)
+			}
+		}
+	}
+	for _, v := range ticks(h, style.Major) {
+		for _, u := range ticks(w, style.Major) {
+			c.Circle(x+u, y+v, majorsize, style.MajorColor)
+		}
+	}
+}
+
+// absStrokedCircle makes an outlined circle centered at (x, y)
+func (c *Canvas) absStrokedCircle(x, y, radius, size float32, strokecolor color.NRGBA) {
+	paint.FillShape(c.Context.Ops, strokecolor, clip.Stroke{Path: c.circlePath(x, y, radius), Width: size}.Op())
+}
+
+// PolarGrid makes a polar grid centered at (x, y) with radius r: rings at the major and minor
+// intervals, and the number of major spokes, with divisions minor spokes between each.
+// Labels show ring radii, and spoke angles in degrees.
+func (c *Canvas) PolarGrid(x, y, r float32, spokes, divisions int, style GridStyle) {
+	majorsize, minorsize := style.sizes()
+	px, py := dimen(x, y, c.Width, c.Height)
+	for _, v := range ticks(r, style.Minor) {
+		if v > 0 && !onMajor(v, style.Major) {
+			c.absStrokedCircle(px, py, pct(v, c.Width), pct(minorsize, c.Width), style.MinorColor)
+		}
+	}
+	if spokes > 0 && divisions > 1 {
+		inner := style.Major
+		n := spokes * divisions
+		for i := 0; i < n; i++ {
+			if i%divisions != 0 {
+				a := 2 * math.Pi * float32(i) / float32(n)
+				x0, y0 := c.Polar(x, y, inner, a)
+				x1, y1 := c.Polar(x, y, r, a)
+				c.Line(x0, y0, x1, y1, minorsize, style.MinorColor)
+			}
+		}
+	}
+	for _, v := range ticks(r, style.Major) {
+		if v > 0 {
+			c.absStrokedCircle(px, py, pct(v, c.Width), pct(majorsize, c.Width), style.MajorColor)
+		}
+	}
+	for i := 0; i < spokes; i++ {
+		a := 2 * math.Pi * float32(i) / float32(spokes)
+		x1, y1 := c.Polar(x, y, r, a)
+		c.Line(x, y, x1, y1, majorsize, style.MajorColor)
+	}
+	ls := style.LabelSize
+	if ls <= 0 {
+		return
+	}
+	for _, v := range ticks(r, style.Major) {
+		if v > 0 {
+			c.CText(x+v, y+ls/2, ls, gridlabel(float64(v)), style.LabelColor)
+		}
+	}
+	for i := 0; i < spokes; i++ {
+		a := 2 * math.Pi * float32(i) / float32(spokes)
+		lx, ly := c.Polar(x, y, r+ls*1.5, a)
+		c.CText(lx, ly-ls/3, ls, gridlabel(float64(360*i)/float64(spokes)), style.LabelColor)
+	}
+}
+
+// IsometricGrid makes a grid of vertical lines, and lines at 30 degrees either side of horizontal,
+// forming equilateral triangles. The intervals are the distances between the vertical lines.
+func (c *Canvas) IsometricGrid(x, y, w, h float32, style GridStyle) {
+	majorsize, minorsize := style.sizes()
+	left, top := dimen(x, y+h, c.Width, c.Height)
+	right, bottom := dimen(x+w, y, c.Width, c.Height)
+	stack := clip.Rect(image.Rect(int(left), int(top), int(math.Ceil(float64(right))), int(math.Ceil(float64(bottom))))).Push(c.Context.Ops)
+	defer stack.Pop()
+
+	tan30 := float32(math.Tan(math.Pi / 6))
+	width, height := right-left, bottom-top
+	lines := func(interval, size float32, fillcolor color.NRGBA, minor bool) {
+		d := pct(interval, c.Width)        // distance between verticals
+		a := d * 2 / float32(math.Sqrt(3)) // vertical distance between diagonals
+		size = pct(size, c.Width)
+		for _, v := range ticks(w, interval) {
+			if !minor || !onMajor(v, style.Major) {
+				u := left + pct(v, c.Width)
+				c.absStrokedPath([]f32.Point{{X: u, Y: top}, {X: u, Y: bottom}}, size, fillcolor)
+			}
+		}
+		// diagonals cross the left edge from above the top, to below the bottom
+		rise := width * tan30
+		n := int(math.Ceil(float64((height + rise) / a)))
+		for k := -n; k <= n; k++ {
+			if minor && onMajor(float32(k)*interval, style.Major) {
+				continue
+			}
+			y0 := top + float32(k)*a
+			c.absStrokedPath([]f32.Point{{X: left, Y: y0}, {X: right, Y: y0 + rise}}, size, fillcolor)
+			c.absStrokedPath([]f32.Point{{X: left, Y: y0}, {X: right, Y: y0 - rise}}, size, fillcolor)
+		}
+	}
+	if style.Minor > 0 {
+		lines(style.Minor, minorsize, style.MinorColor, true)
+	}
+	if style.Major > 0 {
+		lines(style.Major, majorsize, style.MajorColor, false)
+	}
+}
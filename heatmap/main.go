@@ -0,0 +1,58 @@
+// heatmap shows values as colors using color scales
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image/color"
+	"math"
+	"os"
+
+	"github.com/ajstarks/giocanvas"
+)
+
+var schemes = map[string][]color.NRGBA{
+	"viridis": giocanvas.Viridis,
+	"magma":   giocanvas.Magma,
+	"blues":   giocanvas.Blues,
+	"rdbu":    giocanvas.RdBu,
+}
+
+// wave is the value shown at (x, y)
+func wave(x, y float64) float64 {
+	return math.Sin(x/8) * math.Cos(y/11)
+}
+
+func main() {
+	var scheme string
+	var classes int
+	flag.StringVar(&scheme, "scheme", "viridis", "color scheme (viridis, magma, blues, rdbu)")
+	flag.IntVar(&classes, "classes", 0, "number of classes (0 for continuous)")
+	flag.Parse()
+
+	colors, ok := schemes[scheme]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown scheme %q\n", scheme)
+		os.Exit(1)
+	}
+	scale := giocanvas.NewColorScale(colors, -1, 0, 1)
+	if classes > 0 {
+		scale.Quantize(classes)
+	}
+
+	const cell = 2.5
+	textcolor := giocanvas.ColorLookup("white")
+	giocanvas.Run(giocanvas.Sketch{
+		Title:      "heatmap",
+		Background: giocanvas.ColorLookup("black"),
+		Draw: func(canvas *giocanvas.Canvas) {
+			for y := 20.0; y < 90; y += cell {
+				for x := 10.0; x < 90; x += cell {
+					canvas.CornerRect(float32(x), float32(y+cell), cell, cell, scale.Color(wave(x, y)))
+				}
+			}
+			canvas.TextMid(50, 93, 3, "sin(x/8) cos(y/11): "+scheme, textcolor)
+			scale.Legend(canvas, 10, 15, 80, 3, 5, 1.5, textcolor)
+		},
+	})
+}
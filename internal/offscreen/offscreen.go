@@ -0,0 +1,43 @@
+// Package offscreen draws operation lists into images, with the GPU but without a window.
+package offscreen
+
+import (
+	"image"
+	"os"
+	"sync"
+
+	"gioui.org/gpu/headless"
+	"gioui.org/op"
+)
+
+// windows are the offscreen windows, by size
+var windows = struct {
+	sync.Mutex
+	w map[image.Point]*headless.Window
+}{w: make(map[image.Point]*headless.Window)}
+
+// Render draws ops offscreen, returning the image.
+// Without a display, Mesa's surfaceless EGL platform is used.
+func Render(width, height int, ops *op.Ops) (*image.RGBA, error) {
+	windows.Lock()
+	defer windows.Unlock()
+	size := image.Pt(width, height)
+	w, ok := windows.w[size]
+	if !ok {
+		if os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == "" && os.Getenv("EGL_PLATFORM") == "" {
+			os.Setenv("EGL_PLATFORM", "surfaceless")
+		}
+		var err error
+		w, err = headless.NewWindow(width, height)
+		if err != nil {
+			return nil, err
+		}
+		windows.w[size] = w
+	}
+	if err := w.Frame(ops); err != nil {
+		return nil, err
+	}
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	err := w.Screenshot(img)
+	return img, err
+}
@@ -0,0 +1,104 @@
+package giocanvas
+
+import (
+	"sort"
+
+	"gioui.org/op"
+	"gioui.org/op/paint"
+)
+
+// Layers. A layer is a named list of drawing operations that is kept between frames,
+// so that parts of a drawing that seldom change (a background, say) may be drawn once,
+// while an overlay is redrawn for every frame. Draw into a layer with DrawLayer,
+// and draw the layers onto the canvas with Composite.
+
+// Layer is a named list of drawing operations, with a stacking order, visibility and opacity
+type Layer struct {
+	Name    string
+	Z       int     // stacking order: layers with higher Z are drawn over lower ones
+	Visible bool    // only visible layers are drawn
+	Opacity float32 // 0 (transparent) to 1 (opaque)
+	ops     op.Ops
+	call    op.CallOp
+	drawn   bool
+}
+
+// NewLayer makes an empty layer, visible and opaque
+func NewLayer(name string, z int) *Layer {
+	return &Layer{Name: name, Z: z, Visible: true, Opacity: 1}
+}
+
+// Drawn reports whether the layer holds a drawing, made by DrawLayer since it was last cleared
+func (l *Layer) Drawn() bool {
+	return l.drawn
+}
+
+// Clear empties the layer, so that it is redrawn; for example, when the canvas changes size
+func (l *Layer) Clear() {
+	l.ops.Reset()
+	l.call = op.CallOp{}
+	l.drawn = false
+}
+
+// Layers is a set of layers, kept between frames
+type Layers struct {
+	list []*Layer
+}
+
+// Layer returns the named layer. A new layer is added above the others.
+func (ls *Layers) Layer(name string) *Layer {
+	for _, l := range ls.list {
+		if l.Name == name {
+			return l
+		}
+	}
+	z := 0
+	for _, l := range ls.list {
+		if l.Z >= z {
+			z = l.Z + 1
+		}
+	}
+	l := NewLayer(name, z)
+	ls.list = append(ls.list, l)
+	return l
+}
+
+// Order returns the layers from bottom to top; layers with the same Z are in the order they were added
+func (ls *Layers) Order() []*Layer {
+	order := append([]*Layer(nil), ls.list...)
+	sort.SliceStable(order, func(i, j int) bool { return order[i].Z < order[j].Z })
+	return order
+}
+
+// DrawLayer replaces the contents of a layer with the drawing made by the draw function.
+// While draw runs, all canvas methods draw into the layer.
+func (c *Canvas) DrawLayer(l *Layer, draw func()) {
+	l.Clear()
+	ops := c.Context.Ops
+	c.Context.Ops = &l.ops
+	macro := op.Record(&l.ops)
+	draw()
+	l.call = macro.Stop()
+	l.drawn = true
+	c.Context.Ops = ops
+}
+
+// CompositeLayer draws a layer onto the canvas, if it is visible
+func (c *Canvas) CompositeLayer(l *Layer) {
+	if !l.Visible || !l.drawn || l.Opacity <= 0 {
+		return
+	}
+	ops := c.Context.Ops
+	if l.Opacity < 1 {
+		stack := paint.PushOpacity(ops, l.Opacity)
+		defer stack.Pop()
+	}
+	l.call.Add(ops)
+}
+
+// Composite draws the visible layers onto the canvas, from bottom to top
+func (c *Canvas) Composite(ls *Layers) {
+	for _, l := range ls.Order() {
+		c.CompositeLayer(l)
+	}
+}
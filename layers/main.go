@@ -0,0 +1,72 @@
+// layers draws a busy background once, in a cached layer, and redraws a pointer overlay every frame
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"gioui.org/io/key"
+	"gioui.org/io/pointer"
+	"github.com/ajstarks/giocanvas"
+)
+
+func main() {
+	var ndots int
+	var seed int64
+	flag.IntVar(&ndots, "n", 20000, "number of background dots")
+	flag.Int64Var(&seed, "seed", 0, "random seed (0: use the time)")
+	flag.Parse()
+	seed = giocanvas.NewRand(seed).Seed()
+	fmt.Fprintf(os.Stderr, "seed: %d\n", seed)
+
+	var layers giocanvas.Layers
+	background := layers.Layer("background")
+	overlay := layers.Layer("overlay")
+	var px, py float32 = 50, 50
+	var width, height float32
+
+	giocanvas.Run(giocanvas.Sketch{
+		Title:      "layers: B toggles the background, O changes the overlay opacity",
+		Background: giocanvas.ColorLookup("white"),
+		Draw: func(canvas *giocanvas.Canvas) {
+			if canvas.Width != width || canvas.Height != height {
+				width, height = canvas.Width, canvas.Height
+				background.Clear()
+			}
+			if !background.Drawn() {
+				canvas.DrawLayer(background, func() {
+					r := giocanvas.NewRand(seed)
+					x, y := make([]float32, ndots), make([]float32, ndots)
+					for i := range x {
+						x[i], y[i] = r.Range32(0, 100), r.Range32(0, 100)
+					}
+					canvas.Circles(x, y, []float32{0.2}, giocanvas.ColorLookup("steelblue"))
+					canvas.Grid(0, 0, 100, 100, 0.1, 10, giocanvas.ColorLookup("gray"))
+				})
+			}
+			canvas.DrawLayer(overlay, func() {
+				canvas.Circle(px, py, 5, giocanvas.ColorLookup("maroon"))
+				canvas.TextMid(px, py-9, 2, fmt.Sprintf("(%.1f, %.1f)", px, py), giocanvas.ColorLookup("black"))
+			})
+			canvas.Composite(&layers)
+		},
+		Pointer: func(x, y float32, e pointer.Event) {
+			px, py = x, y
+		},
+		Key: func(e key.Event) {
+			if e.State != key.Press {
+				return
+			}
+			switch e.Name {
+			case "B":
+				background.Visible = !background.Visible
+			case "O":
+				overlay.Opacity -= 0.25
+				if overlay.Opacity <= 0 {
+					overlay.Opacity = 1
+				}
+			}
+		},
+	})
+}
@@ -0,0 +1,88 @@
+// lsystem draws Lindenmayer systems with turtle graphics
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/ajstarks/giocanvas"
+)
+
+// preset is an L-system, with the turtle's starting position and heading,
+// its step at the first generation, and how much the step shrinks in each generation
+type preset struct {
+	system       giocanvas.LSystem
+	x, y         float32
+	heading      float64
+	step, shrink float32
+}
+
+var presets = map[string]preset{
+	"koch": {
+		system: giocanvas.LSystem{Axiom: "F--F--F", Rules: map[rune]string{'F': "F+F--F+F"}, Angle: 60},
+		x:      20, y: 70, heading: 0, step: 60, shrink: 3,
+	},
+	"sierpinski": {
+		system: giocanvas.LSystem{Axiom: "F-G-G", Rules: map[rune]string{'F': "F-G+F+G-F", 'G': "GG"}, Angle: 120},
+		x:      10, y: 10, heading: 60, step: 80, shrink: 2,
+	},
+	"dragon": {
+		system: giocanvas.LSystem{Axiom: "FX", Rules: map[rune]string{'X': "X+YF+", 'Y': "-FX-Y"}, Angle: 90},
+		x:      35, y: 60, heading: 0, step: 40, shrink: float32(math.Sqrt2),
+	},
+	"plant": {
+		system: giocanvas.LSystem{Axiom: "X", Rules: map[rune]string{'X': "F+[[X]-X]-F[-FX]+X", 'F': "FF"}, Angle: 25},
+		x:      30, y: 5, heading: 65, step: 30, shrink: 2,
+	},
+	"hilbert": {
+		system: giocanvas.LSystem{Axiom: "A", Rules: map[rune]string{'A': "+BF-AFA-FB+", 'B': "-AF+BFB+FA-"}, Angle: 90},
+		x:      10, y: 10, heading: 0, step: 80, shrink: 2,
+	},
+}
+
+// names returns the sorted preset names
+func names() []string {
+	var n []string
+	for k := range presets {
+		n = append(n, k)
+	}
+	sort.Strings(n)
+	return n
+}
+
+func main() {
+	var name, fg, bg string
+	var generations int
+	var width float64
+	flag.StringVar(&name, "system", "plant", fmt.Sprintf("system %v", names()))
+	flag.IntVar(&generations, "n", 5, "generations")
+	flag.Float64Var(&width, "lw", 0.15, "line width")
+	flag.StringVar(&fg, "color", "darkgreen", "line color")
+	flag.StringVar(&bg, "bgcolor", "white", "background color")
+	flag.Parse()
+
+	p, ok := presets[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown system %q, choose from %v\n", name, names())
+		os.Exit(1)
+	}
+	program := p.system.Expand(generations)
+	step := p.step / float32(math.Pow(float64(p.shrink), float64(generations)))
+	if name == "hilbert" {
+		step = p.step / float32(math.Pow(2, float64(generations))-1)
+	}
+	linecolor := giocanvas.ColorLookup(fg)
+	giocanvas.Run(giocanvas.Sketch{
+		Title:      "lsystem: " + name,
+		Background: giocanvas.ColorLookup(bg),
+		Draw: func(canvas *giocanvas.Canvas) {
+			t := canvas.NewTurtle(p.x, p.y, p.heading)
+			t.PenColor(linecolor)
+			t.PenWidth(float32(width))
+			t.Run(program, step, p.system.Angle)
+		},
+	})
+}
@@ -0,0 +1,426 @@
+package palette
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	gc "github.com/ajstarks/giocanvas"
+)
+
+// Readers and writers for palette file formats.
+// Palettes that are not named in a file are read with the empty name.
+
+// hexcolor returns the color as #rrggbb, or #rrggbbaa if not opaque
+func hexcolor(c color.NRGBA) string {
+	if c.A == 255 {
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+}
+
+// parsecolor parses a color, allowing hex colors without the leading #
+func parsecolor(s string) (color.NRGBA, error) {
+	if n := len(s); n == 6 || n == 8 {
+		if _, err := strconv.ParseUint(s, 16, 32); err == nil {
+			s = "#" + s
+		}
+	}
+	return gc.ParseColor(s)
+}
+
+// single returns the only palette in a map, for formats that hold one palette
+func single(m Map, format string) (string, Palette, error) {
+	if len(m) != 1 {
+		return "", nil, fmt.Errorf("%s: a file holds one palette, not %d", format, len(m))
+	}
+	for name, p := range m {
+		return name, p, nil
+	}
+	return "", nil, nil
+}
+
+// ReadPal reads palettes in the .pal format: one palette per line, a name followed by colors.
+//
+//	rainbow #ff0000 #ffa500 #ffff00 #008000 #0000ff #4b0082 #ee82ee
+//
+// Colors that cannot be parsed are skipped, as are lines without colors.
+func ReadPal(r io.Reader) (Map, error) {
+	scanner := bufio.NewScanner(r)
+	m := make(Map)
+	for scanner.Scan() {
+		args := strings.Fields(scanner.Text())
+		if len(args) < 2 {
+			continue
+		}
+		var p Palette
+		for _, s := range args[1:] {
+			if c, err := gc.ParseColor(s); err == nil {
+				p = append(p, c)
+			}
+		}
+		if len(p) > 0 {
+			m[args[0]] = p
+		}
+	}
+	return m, scanner.Err()
+}
+
+// WritePal writes palettes in the .pal format, sorted by name
+func WritePal(w io.Writer, m Map) error {
+	bw := bufio.NewWriter(w)
+	for _, name := range m.Names() {
+		fmt.Fprintf(bw, "%-22s", name)
+		for _, c := range m[name] {
+			fmt.Fprintf(bw, " %s", hexcolor(c))
+		}
+		fmt.Fprintln(bw)
+	}
+	return bw.Flush()
+}
+
+// ReadGPL reads a GIMP palette
+func ReadGPL(r io.Reader) (Map, error) {
+	scanner := bufio.NewScanner(r)
+	var name string
+	var p Palette
+	line := 0
+	for scanner.Scan() {
+		line++
+		s := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == 1:
+			if s != "GIMP Palette" {
+				return nil, errors.New("gpl: missing GIMP Palette header")
+			}
+		case len(s) == 0 || s[0] == '#':
+			continue
+		case strings.HasPrefix(s, "Name:"):
+			name = strings.TrimSpace(s[5:])
+		case strings.HasPrefix(s, "Columns:"):
+			continue
+		default:
+			f := strings.Fields(s)
+			if len(f) < 3 {
+				return nil, fmt.Errorf("gpl: line %d: want red, green and blue values", line)
+			}
+			var rgb [3]uint8
+			for i := range rgb {
+				v, err := strconv.ParseUint(f[i], 10, 8)
+				if err != nil {
+					return nil, fmt.Errorf("gpl: line %d: bad value %q", line, f[i])
+				}
+				rgb[i] = uint8(v)
+			}
+			p = append(p, color.NRGBA{rgb[0], rgb[1], rgb[2], 255})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return Map{name: p}, nil
+}
+
+// WriteGPL writes a single palette in the GIMP format
+func WriteGPL(w io.Writer, m Map) error {
+	name, p, err := single(m, "gpl")
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "GIMP Palette\nName: %s\n#\n", name)
+	for _, c := range p {
+		fmt.Fprintf(bw, "%3d %3d %3d\t%s\n", c.R, c.G, c.B, hexcolor(c))
+	}
+	return bw.Flush()
+}
+
+// ReadHex reads a Lospec .hex palette: one rrggbb color per line
+func ReadHex(r io.Reader) (Map, error) {
+	scanner := bufio.NewScanner(r)
+	var p Palette
+	line := 0
+	for scanner.Scan() {
+		line++
+		s := strings.TrimSpace(scanner.Text())
+		if len(s) == 0 {
+			continue
+		}
+		c, err := parsecolor(s)
+		if err != nil {
+			return nil, fmt.Errorf("hex: line %d: %v", line, err)
+		}
+		p = append(p, c)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return Map{"": p}, nil
+}
+
+// WriteHex writes a single palette in the Lospec .hex format
+func WriteHex(w io.Writer, m Map) error {
+	_, p, err := single(m, "hex")
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	for _, c := range p {
+		fmt.Fprintln(bw, hexcolor(c)[1:])
+	}
+	return bw.Flush()
+}
+
+// ReadJSON reads palettes in JSON. A file may be an object of named color lists,
+//
+//	{"rainbow": ["#ff0000", "orange", "yellow"], "gray": ["#000", "#777", "#fff"]}
+//
+// a Lospec palette object,
+//
+//	{"name": "rainbow", "colors": ["ff0000", "ffa500", "ffff00"]}
+//
+// or a list of colors.
+func ReadJSON(r io.Reader) (Map, error) {
+	var v interface{}
+	if err := json.NewDecoder(r).Decode(&v); err != nil {
+		return nil, fmt.Errorf("json: %v", err)
+	}
+	m := make(Map)
+	switch v := v.(type) {
+	case []interface{}:
+		p, err := jsoncolors(v)
+		if err != nil {
+			return nil, err
+		}
+		m[""] = p
+	case map[string]interface{}:
+		if colors, ok := v["colors"].([]interface{}); ok {
+			name, _ := v["name"].(string)
+			p, err := jsoncolors(colors)
+			if err != nil {
+				return nil, err
+			}
+			m[name] = p
+			break
+		}
+		for name, colors := range v {
+			list, ok := colors.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("json: palette %q is not a list of colors", name)
+			}
+			p, err := jsoncolors(list)
+			if err != nil {
+				return nil, err
+			}
+			m[name] = p
+		}
+	default:
+		return nil, errors.New("json: want an object or a list of colors")
+	}
+	return m, nil
+}
+
+// jsoncolors makes a palette from a decoded JSON list
+func jsoncolors(list []interface{}) (Palette, error) {
+	p := make(Palette, len(list))
+	for i, v := range list {
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("json: color %v is not a string", v)
+		}
+		c, err := parsecolor(s)
+		if err != nil {
+			return nil, fmt.Errorf("json: %v", err)
+		}
+		p[i] = c
+	}
+	return p, nil
+}
+
+// WriteJSON writes palettes as a JSON object of named color lists
+func WriteJSON(w io.Writer, m Map) error {
+	out := make(map[string][]string, len(m))
+	for name, p := range m {
+		colors := make([]string, len(p))
+		for i, c := range p {
+			colors[i] = hexcolor(c)
+		}
+		out[name] = colors
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// Adobe Swatch Exchange block types
+const (
+	aseGroupStart = 0xc001
+	aseGroupEnd   = 0xc002
+	aseColor      = 0x0001
+	aseMaxBlock   = 1 << 18 // longer than a block with the longest name
+)
+
+// ReadASE reads an Adobe Swatch Exchange file. Each group is a palette;
+// colors outside of groups are read into the palette with the empty name.
+func ReadASE(r io.Reader) (Map, error) {
+	var header struct {
+		Signature [4]byte
+		Major     uint16
+		Minor     uint16
+		Blocks    uint32
+	}
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+		return nil, fmt.Errorf("ase: %v", err)
+	}
+	if string(header.Signature[:]) != "ASEF" {
+		return nil, errors.New("ase: missing ASEF signature")
+	}
+	m := make(Map)
+	group := ""
+	for i := uint32(0); i < header.Blocks; i++ {
+		var block struct {
+			Type   uint16
+			Length uint32
+		}
+		if err := binary.Read(r, binary.BigEndian, &block); err != nil {
+			return nil, fmt.Errorf("ase: block %d: %v", i, err)
+		}
+		if block.Length > aseMaxBlock {
+			return nil, fmt.Errorf("ase: block %d: length %d is too long", i, block.Length)
+		}
+		data := make([]byte, block.Length)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, fmt.Errorf("ase: block %d: %v", i, err)
+		}
+		br := bytes.NewReader(data)
+		switch block.Type {
+		case aseGroupStart:
+			name, err := asename(br)
+			if err != nil {
+				return nil, fmt.Errorf("ase: block %d: %v", i, err)
+			}
+			group = name
+		case aseGroupEnd:
+			group = ""
+		case aseColor:
+			c, err := asecolor(br)
+			if err != nil {
+				return nil, fmt.Errorf("ase: block %d: %v", i, err)
+			}
+			m[group] = append(m[group], c)
+		}
+	}
+	return m, nil
+}
+
+// asename reads a length-prefixed, null terminated UTF-16 name
+func asename(r io.Reader) (string, error) {
+	var n uint16
+	if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+		return "", err
+	}
+	u := make([]uint16, n)
+	if err := binary.Read(r, binary.BigEndian, u); err != nil {
+		return "", err
+	}
+	if n > 0 && u[n-1] == 0 {
+		u = u[:n-1]
+	}
+	return string(utf16.Decode(u)), nil
+}
+
+// asecolor reads a color entry, converting from its color model to sRGB
+func asecolor(r io.Reader) (color.NRGBA, error) {
+	if _, err := asename(r); err != nil {
+		return color.NRGBA{}, err
+	}
+	var model [4]byte
+	if err := binary.Read(r, binary.BigEndian, &model); err != nil {
+		return color.NRGBA{}, err
+	}
+	var nv int
+	switch string(model[:]) {
+	case "RGB ", "LAB ":
+		nv = 3
+	case "CMYK":
+		nv = 4
+	case "Gray":
+		nv = 1
+	default:
+		return color.NRGBA{}, fmt.Errorf("unknown color model %q", model[:])
+	}
+	v := make([]float32, nv)
+	if err := binary.Read(r, binary.BigEndian, v); err != nil {
+		return color.NRGBA{}, err
+	}
+	switch string(model[:]) {
+	case "RGB ":
+		return color.NRGBA{unit8(v[0]), unit8(v[1]), unit8(v[2]), 255}, nil
+	case "CMYK":
+		k := 1 - v[3]
+		return color.NRGBA{unit8((1 - v[0]) * k), unit8((1 - v[1]) * k), unit8((1 - v[2]) * k), 255}, nil
+	case "Gray":
+		g := unit8(v[0])
+		return color.NRGBA{g, g, g, 255}, nil
+	default: // LAB, lightness is 0-1
+		return gc.ParseColor(fmt.Sprintf("lab(%g %g %g)", v[0]*100, v[1], v[2]))
+	}
+}
+
+// unit8 converts a 0-1 value to 0-255
+func unit8(v float32) uint8 {
+	return uint8(math.Round(math.Max(0, math.Min(1, float64(v))) * 255))
+}
+
+// WriteASE writes palettes as an Adobe Swatch Exchange file, one group for each palette
+func WriteASE(w io.Writer, m Map) error {
+	var buf bytes.Buffer
+	var blocks uint32
+	block := func(kind uint16, data []byte) {
+		binary.Write(&buf, binary.BigEndian, kind)
+		binary.Write(&buf, binary.BigEndian, uint32(len(data)))
+		buf.Write(data)
+		blocks++
+	}
+	for _, name := range m.Names() {
+		block(aseGroupStart, asestring(name))
+		for _, c := range m[name] {
+			var data bytes.Buffer
+			data.Write(asestring(hexcolor(c)))
+			data.WriteString("RGB ")
+			binary.Write(&data, binary.BigEndian, [3]float32{float32(c.R) / 255, float32(c.G) / 255, float32(c.B) / 255})
+			binary.Write(&data, binary.BigEndian, uint16(2)) // normal color
+			block(aseColor, data.Bytes())
+		}
+		block(aseGroupEnd, nil)
+	}
+	if _, err := w.Write([]byte("ASEF")); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, [2]uint16{1, 0}); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, blocks); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// asestring encodes a name as a length-prefixed, null terminated UTF-16 string
+func asestring(s string) []byte {
+	u := append(utf16.Encode([]rune(s)), 0)
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, uint16(len(u)))
+	binary.Write(&buf, binary.BigEndian, u)
+	return buf.Bytes()
+}
@@ -0,0 +1,184 @@
+// Package palette reads, writes and uses color palettes
+package palette
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	gc "github.com/ajstarks/giocanvas"
+)
+
+// Palette is a list of colors
+type Palette []color.NRGBA
+
+// Map is a set of named palettes
+type Map map[string]Palette
+
+// builtin is the table of built-in palettes (from https://lospec.com/palette-list)
+var builtin = map[string][]string{
+	"kirokaze-gameboy":       {"#332c50", "#46878f", "#94e344", "#e2f3e4"},
+	"ice-cream-gb":           {"#7c3f58", "#eb6b6f", "#f9a875", "#fff6d3"},
+	"2-bit-demichrome":       {"#211e20", "#555568", "#a0a08b", "#e9efec"},
+	"mist-gb":                {"#2d1b00", "#1e606e", "#5ab9a8", "#c4f0c2"},
+	"rustic-gb":              {"#2c2137", "#764462", "#edb4a1", "#a96868"},
+	"2-bit-grayscale":        {"#000000", "#676767", "#b6b6b6", "#ffffff"},
+	"hollow":                 {"#0f0f1b", "#565a75", "#c6b7be", "#fafbf6"},
+	"ayy4":                   {"#00303b", "#ff7777", "#ffce96", "#f1f2da"},
+	"nintendo-gameboy-bgb":   {"#081820", "#346856", "#88c070", "#e0f8d0"},
+	"red-brick":              {"#eff9d6", "#ba5044", "#7a1c4b", "#1b0326"},
+	"nostalgia":              {"#d0d058", "#a0a840", "#708028", "#405010"},
+	"spacehaze":              {"#f8e3c4", "#cc3495", "#6b1fb1", "#0b0630"},
+	"moonlight-gb":           {"#0f052d", "#203671", "#36868f", "#5fc75d"},
+	"links-awakening-sgb":    {"#5a3921", "#6b8c42", "#7bc67b", "#ffffb5"},
+	"arq4":                   {"#ffffff", "#6772a9", "#3a3277", "#000000"},
+	"blk-aqu4":               {"#002b59", "#005f8c", "#00b9be", "#9ff4e5"},
+	"pokemon-sgb":            {"#181010", "#84739c", "#f7b58c", "#ffefff"},
+	"nintendo-super-gameboy": {"#331e50", "#a63725", "#d68e49", "#f7e7c6"},
+	"blu-scribbles":          {"#051833", "#0a4f66", "#0f998e", "#12cc7f"},
+	"kankei4":                {"#ffffff", "#f42e1f", "#2f256b", "#060608"},
+	"dark-mode":              {"#212121", "#454545", "#787878", "#a8a5a5"},
+	"pen-n-paper":            {"#e4dbba", "#a4929a", "#4f3a54", "#260d1c"},
+}
+
+// Builtin returns a copy of the built-in palettes
+func Builtin() Map {
+	m := make(Map, len(builtin))
+	for name, colors := range builtin {
+		p := make(Palette, len(colors))
+		for i, c := range colors {
+			p[i] = gc.ColorLookup(c)
+		}
+		m[name] = p
+	}
+	return m
+}
+
+// Parse makes a palette from a list of colors (names, hex, or any form understood by giocanvas.ParseColor)
+func Parse(colors []string) (Palette, error) {
+	p := make(Palette, len(colors))
+	for i, s := range colors {
+		c, err := gc.ParseColor(s)
+		if err != nil {
+			return nil, err
+		}
+		p[i] = c
+	}
+	return p, nil
+}
+
+// Color returns the ith color, cycling through the palette
+func (p Palette) Color(i int) color.NRGBA {
+	n := len(p)
+	if n == 0 {
+		return color.NRGBA{}
+	}
+	return p[((i%n)+n)%n]
+}
+
+// Random returns a randomly chosen color from the palette
+func (p Palette) Random() color.NRGBA {
+	if len(p) == 0 {
+		return color.NRGBA{}
+	}
+	return p[rand.Intn(len(p))]
+}
+
+// Cycle returns a function that returns the palette colors in turn, starting over at the end
+func (p Palette) Cycle() func() color.NRGBA {
+	i := -1
+	return func() color.NRGBA {
+		i++
+		return p.Color(i)
+	}
+}
+
+// Names returns the sorted palette names
+func (m Map) Names() []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Random returns the name of a randomly chosen palette, and the palette
+func (m Map) Random() (string, Palette) {
+	names := m.Names()
+	if len(names) == 0 {
+		return "", nil
+	}
+	name := names[rand.Intn(len(names))]
+	return name, m[name]
+}
+
+// format returns the palette format from a filename's extension
+func format(filename string) string {
+	return strings.ToLower(strings.TrimPrefix(filepath.Ext(filename), "."))
+}
+
+// Load reads palettes from a file. The format is determined by the extension:
+// .gpl (GIMP), .hex (Lospec), .ase (Adobe Swatch Exchange), or .json;
+// files with other extensions are read in the .pal format.
+// Palettes without names in the file are named by the file's base name.
+func Load(filename string) (Map, error) {
+	r, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	var m Map
+	switch format(filename) {
+	case "gpl":
+		m, err = ReadGPL(r)
+	case "hex":
+		m, err = ReadHex(r)
+	case "ase":
+		m, err = ReadASE(r)
+	case "json":
+		m, err = ReadJSON(r)
+	default:
+		m, err = ReadPal(r)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	if p, ok := m[""]; ok {
+		delete(m, "")
+		m[strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))] = p
+	}
+	return m, nil
+}
+
+// Save writes palettes to a file, in the format given by the extension (see Load).
+// The .gpl and .hex formats hold a single palette.
+func Save(filename string, m Map) error {
+	var write func(io.Writer, Map) error
+	switch format(filename) {
+	case "gpl":
+		write = WriteGPL
+	case "hex":
+		write = WriteHex
+	case "ase":
+		write = WriteASE
+	case "json":
+		write = WriteJSON
+	default:
+		write = WritePal
+	}
+	w, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := write(w, m); err != nil {
+		w.Close()
+		return fmt.Errorf("%s: %v", filename, err)
+	}
+	return w.Close()
+}
@@ -0,0 +1,132 @@
+package palette
+
+import (
+	"bytes"
+	"image/color"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var rainbow = Palette{{255, 0, 0, 255}, {255, 165, 0, 255}, {255, 255, 0, 255}, {0, 128, 0, 255}}
+
+func TestRoundTrip(t *testing.T) {
+	m := Map{"rainbow": rainbow}
+	formats := []struct {
+		name  string
+		write func(io.Writer, Map) error
+		read  func(io.Reader) (Map, error)
+		named bool
+	}{
+		{"pal", WritePal, ReadPal, true},
+		{"gpl", WriteGPL, ReadGPL, true},
+		{"hex", WriteHex, ReadHex, false},
+		{"ase", WriteASE, ReadASE, true},
+		{"json", WriteJSON, ReadJSON, true},
+	}
+	for _, f := range formats {
+		var buf bytes.Buffer
+		if err := f.write(&buf, m); err != nil {
+			t.Errorf("%s: write: %v", f.name, err)
+			continue
+		}
+		got, err := f.read(&buf)
+		if err != nil {
+			t.Errorf("%s: read: %v", f.name, err)
+			continue
+		}
+		name := "rainbow"
+		if !f.named {
+			name = ""
+		}
+		if !reflect.DeepEqual(got, Map{name: rainbow}) {
+			t.Errorf("%s: got %v, want %v", f.name, got, rainbow)
+		}
+	}
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name string
+		read func(io.Reader) (Map, error)
+		data string
+		want Map
+	}{
+		{"pal", ReadPal, "ajstarks  #aa0000 #aaaaaa\n\nrgb red lime blue\n",
+			Map{"ajstarks": {{170, 0, 0, 255}, {170, 170, 170, 255}}, "rgb": {{255, 0, 0, 255}, {0, 255, 0, 255}, {0, 0, 255, 255}}}},
+		{"pal with bad colors", ReadPal, "p #ff0000 nocolor\nnone nocolor\n",
+			Map{"p": {{255, 0, 0, 255}}}},
+		{"gpl", ReadGPL, "GIMP Palette\nName: two\nColumns: 2\n# comment\n255   0   0\tred\n  0   0 255\n",
+			Map{"two": {{255, 0, 0, 255}, {0, 0, 255, 255}}}},
+		{"hex", ReadHex, "ff0000\n0000ff80\n",
+			Map{"": {{255, 0, 0, 255}, {0, 0, 255, 128}}}},
+		{"lospec json", ReadJSON, `{"name": "two", "author": "", "colors": ["ff0000", "0000ff"]}`,
+			Map{"two": {{255, 0, 0, 255}, {0, 0, 255, 255}}}},
+		{"json list", ReadJSON, `["red", "#00f"]`,
+			Map{"": {{255, 0, 0, 255}, {0, 0, 255, 255}}}},
+	}
+	for _, test := range tests {
+		got, err := test.read(strings.NewReader(test.data))
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+
+	bad := []struct {
+		name string
+		read func(io.Reader) (Map, error)
+		data string
+	}{
+		{"gpl", ReadGPL, "255 0 0\n"},
+		{"gpl", ReadGPL, "GIMP Palette\n300 0 0\n"},
+		{"hex", ReadHex, "fg0000\n"},
+		{"ase", ReadASE, "ASEB"},
+		{"ase", ReadASE, "ASEF\x00\x01\x00\x00\x00\x00\x00\x01\x00\x01\xff\xff\xff\xff"},
+		{"json", ReadJSON, `{"p": "red"}`},
+	}
+	for _, test := range bad {
+		if _, err := test.read(strings.NewReader(test.data)); err == nil {
+			t.Errorf("%s: %q: expected an error", test.name, test.data)
+		}
+	}
+}
+
+func TestCycle(t *testing.T) {
+	next := rainbow.Cycle()
+	for i := 0; i < 2*len(rainbow); i++ {
+		if c := next(); c != rainbow[i%len(rainbow)] {
+			t.Errorf("cycle %d: got %v", i, c)
+		}
+	}
+	if c := rainbow.Color(-1); c != rainbow[len(rainbow)-1] {
+		t.Errorf("Color(-1): got %v", c)
+	}
+	if c := (Palette{}).Random(); c != (color.NRGBA{}) {
+		t.Errorf("empty Random: got %v", c)
+	}
+	if len(Builtin()) != len(builtin) {
+		t.Errorf("Builtin: got %d palettes, want %d", len(Builtin()), len(builtin))
+	}
+}
+
+func TestLoad(t *testing.T) {
+	// files without a known extension are read in the .pal format
+	name := filepath.Join(t.TempDir(), "colors.txt")
+	if err := os.WriteFile(name, []byte("rainbow #ff0000 #ffa500 #ffff00 #008000\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m, err := Load(name)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !reflect.DeepEqual(m, Map{"rainbow": rainbow}) {
+		t.Errorf("Load: got %v, want %v", m, rainbow)
+	}
+}
@@ -0,0 +1,449 @@
+package giocanvas
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ParseColor parses a color specification using CSS Color Level 4 syntax:
+//
+//	named colors and "transparent"
+//	"#rgb", "#rgba", "#rrggbb", "#rrggbbaa"
+//	"rgb(r g b / a)",  "rgba(r, g, b, a)"
+//	"hsl(h s l / a)",  "hsla(h, s, l, a)"
+//	"hwb(h w b / a)"
+//	"lab(l a b / a)",   "lch(l c h / a)"
+//	"oklab(l a b / a)", "oklch(l c h / a)"
+//	"color(space c1 c2 c3 / a)", where space is srgb, srgb-linear, display-p3, xyz, xyz-d50 or xyz-d65
+//
+// Components may be numbers, percentages or "none", hues may have deg, rad, grad or turn units.
+// Values outside the sRGB gamut are clipped.
+// The giocanvas forms "#rr", "rgb(r)", "rgb(r,g)", "rgb(r,g,b,a)" (a=0-255),
+// and "hsv(h,s,v)", "hsv(h,s,v,a)" (h=0-360, s, v, a=0-100) are also accepted.
+func ParseColor(s string) (color.NRGBA, error) {
+	spec := strings.ToLower(strings.TrimSpace(s))
+	if c, ok := colornames[spec]; ok {
+		return c, nil
+	}
+	if spec == "transparent" {
+		return color.NRGBA{}, nil
+	}
+	var c color.NRGBA
+	var err error
+	switch {
+	case strings.HasPrefix(spec, "#"):
+		c, err = parsehex(spec[1:])
+	case strings.HasSuffix(spec, ")") && strings.Contains(spec, "("):
+		i := strings.Index(spec, "(")
+		c, err = parsefunc(strings.TrimSpace(spec[:i]), spec[i+1:len(spec)-1])
+	case len(spec) == 0:
+		err = fmt.Errorf("empty color")
+	default:
+		err = fmt.Errorf("unknown color name")
+	}
+	if err != nil {
+		return color.NRGBA{0, 0, 0, 255}, fmt.Errorf("color %q: %v", s, err)
+	}
+	return c, nil
+}
+
+// parsehex parses hex colors: rgb, rgba, rrggbb, rrggbbaa (and the giocanvas form rr)
+func parsehex(s string) (color.NRGBA, error) {
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil || len(s) == 0 {
+		return color.NRGBA{}, fmt.Errorf("bad hex digits %q", s)
+	}
+	n := uint8(v)
+	switch len(s) {
+	case 2: // rr
+		return color.NRGBA{n, 0, 0, 255}, nil
+	case 3: // rgb
+		return color.NRGBA{uint8(v>>8) * 0x11, uint8(v>>4&0xf) * 0x11, uint8(v&0xf) * 0x11, 255}, nil
+	case 4: // rgba
+		return color.NRGBA{uint8(v>>12) * 0x11, uint8(v>>8&0xf) * 0x11, uint8(v>>4&0xf) * 0x11, uint8(v&0xf) * 0x11}, nil
+	case 6: // rrggbb
+		return color.NRGBA{uint8(v >> 16), uint8(v >> 8), n, 255}, nil
+	case 8: // rrggbbaa
+		return color.NRGBA{uint8(v >> 24), uint8(v >> 16), uint8(v >> 8), n}, nil
+	}
+	return color.NRGBA{}, fmt.Errorf("hex colors have 3, 4, 6 or 8 digits, not %d", len(s))
+}
+
+// parsefunc parses functional notation, name(args)
+func parsefunc(name, args string) (color.NRGBA, error) {
+	v, alpha, legacy, err := splitargs(args)
+	if err != nil {
+		return color.NRGBA{}, err
+	}
+	a := 1.0
+	if len(alpha) > 0 {
+		if a, err = alphavalue(alpha); err != nil {
+			return color.NRGBA{}, err
+		}
+	}
+	switch name {
+	case "rgb", "rgba":
+		return parsergb(name, v, alpha, legacy)
+	case "hsv":
+		return parsehsv(v, alpha)
+	case "color":
+		if len(v) != 4 || legacy {
+			return color.NRGBA{}, fmt.Errorf("color() needs a color space and 3 space separated components")
+		}
+		r, g, b, err := colorspace(v[0], v[1:])
+		if err != nil {
+			return color.NRGBA{}, err
+		}
+		return nrgba(r, g, b, a), nil
+	}
+	if len(v) != 3 {
+		return color.NRGBA{}, fmt.Errorf("%s() needs 3 components, not %d", name, len(v))
+	}
+	var r, g, b float64
+	switch name {
+	case "hsl", "hsla", "hwb":
+		h, err := hue(v[0])
+		if err != nil {
+			return color.NRGBA{}, err
+		}
+		p, err := components(v[1:], 100, 100)
+		if err != nil {
+			return color.NRGBA{}, err
+		}
+		if name == "hwb" {
+			r, g, b = hwb2rgb(h, p[0]/100, p[1]/100)
+		} else {
+			r, g, b = hsl2rgb(h, p[0]/100, p[1]/100)
+		}
+	case "lab":
+		p, err := components(v, 100, 125, 125)
+		if err != nil {
+			return color.NRGBA{}, err
+		}
+		r, g, b = lab2rgb(p[0], p[1], p[2])
+	case "lch":
+		p, err := components(v[:2], 100, 150)
+		if err != nil {
+			return color.NRGBA{}, err
+		}
+		h, err := hue(v[2])
+		if err != nil {
+			return color.NRGBA{}, err
+		}
+		sin, cos := math.Sincos(h * math.Pi / 180)
+		r, g, b = lab2rgb(p[0], p[1]*cos, p[1]*sin)
+	case "oklab":
+		p, err := components(v, 1, 0.4, 0.4)
+		if err != nil {
+			return color.NRGBA{}, err
+		}
+		r, g, b = oklab2rgb(p[0], p[1], p[2])
+	case "oklch":
+		p, err := components(v[:2], 1, 0.4)
+		if err != nil {
+			return color.NRGBA{}, err
+		}
+		h, err := hue(v[2])
+		if err != nil {
+			return color.NRGBA{}, err
+		}
+		sin, cos := math.Sincos(h * math.Pi / 180)
+		r, g, b = oklab2rgb(p[0], p[1]*cos, p[1]*sin)
+	default:
+		return color.NRGBA{}, fmt.Errorf("unknown color function %q", name)
+	}
+	return nrgba(r, g, b, a), nil
+}
+
+// parsergb parses rgb() and rgba() components: numbers are 0-255, percentages 0-100%.
+// The giocanvas forms rgb(r), rgb(r,g) and rgb(r,g,b,a) with integer alpha 0-255 are allowed.
+func parsergb(name string, v []string, alpha string, legacy bool) (color.NRGBA, error) {
+	if len(v) == 0 || len(v) > 3 || (len(v) < 3 && (name != "rgb" || len(alpha) > 0)) {
+		return color.NRGBA{}, fmt.Errorf("%s() needs 3 components, not %d", name, len(v))
+	}
+	c := color.NRGBA{A: 255}
+	p, err := components(v, 255, 255, 255)
+	if err != nil {
+		return c, err
+	}
+	rgb := []*uint8{&c.R, &c.G, &c.B}
+	for i := range p {
+		*rgb[i] = clamp8(p[i] / 255)
+	}
+	if len(alpha) > 0 {
+		// giocanvas rgb(r,g,b,a) uses integer alpha, 0-255
+		if n, err := strconv.Atoi(alpha); err == nil && name == "rgb" && legacy {
+			c.A = clamp8(float64(n) / 255)
+			return c, nil
+		}
+		a, err := alphavalue(alpha)
+		if err != nil {
+			return c, err
+		}
+		c.A = clamp8(a)
+	}
+	return c, nil
+}
+
+// parsehsv parses the giocanvas hsv(h,s,v) and hsv(h,s,v,a) forms
+func parsehsv(v []string, alpha string) (color.NRGBA, error) {
+	if len(v) != 3 {
+		return color.NRGBA{}, fmt.Errorf("hsv() needs 3 components, not %d", len(v))
+	}
+	p, err := components(v, 360, 100, 100)
+	if err != nil {
+		return color.NRGBA{}, err
+	}
+	c := color.NRGBA{A: 255}
+	c.R, c.G, c.B = hsv2rgb(p[0], math.Min(p[1], 100), math.Min(p[2], 100))
+	if len(alpha) > 0 {
+		a, _, err := number(alpha)
+		if err != nil {
+			return c, err
+		}
+		c.A = clamp8(a / 100)
+	}
+	return c, nil
+}
+
+// splitargs splits function arguments into components and alpha,
+// using either the comma separated (legacy) or space separated syntax
+func splitargs(s string) ([]string, string, bool, error) {
+	var v []string
+	var alpha string
+	if strings.Contains(s, ",") {
+		if strings.Contains(s, "/") {
+			return nil, "", true, fmt.Errorf("cannot mix commas and /")
+		}
+		v = strings.Split(s, ",")
+		for i := range v {
+			v[i] = strings.TrimSpace(v[i])
+			if len(v[i]) == 0 {
+				return nil, "", true, fmt.Errorf("missing component %d", i+1)
+			}
+		}
+		if len(v) == 4 {
+			alpha = v[3]
+			v = v[:3]
+		}
+		return v, alpha, true, nil
+	}
+	parts := strings.Split(s, "/")
+	switch len(parts) {
+	case 1:
+	case 2:
+		alpha = strings.TrimSpace(parts[1])
+		if len(alpha) == 0 {
+			return nil, "", false, fmt.Errorf("missing alpha after /")
+		}
+	default:
+		return nil, "", false, fmt.Errorf("too many / separators")
+	}
+	return strings.Fields(parts[0]), alpha, false, nil
+}
+
+// number parses a number or percentage; "none" is zero
+func number(s string) (float64, bool, error) {
+	if s == "none" {
+		return 0, false, nil
+	}
+	percent := strings.HasSuffix(s, "%")
+	v, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	if err != nil {
+		return 0, percent, fmt.Errorf("bad number %q", s)
+	}
+	return v, percent, nil
+}
+
+// components parses numbers, scaling percentages to the corresponding reference value
+func components(v []string, ref ...float64) ([]float64, error) {
+	p := make([]float64, len(v))
+	for i, s := range v {
+		n, percent, err := number(s)
+		if err != nil {
+			return nil, err
+		}
+		if percent {
+			n = (n / 100) * ref[i]
+		}
+		p[i] = n
+	}
+	return p, nil
+}
+
+// alphavalue parses an alpha value: a number 0-1 or a percentage
+func alphavalue(s string) (float64, error) {
+	a, percent, err := number(s)
+	if err != nil {
+		return 0, fmt.Errorf("bad alpha %q", s)
+	}
+	if percent {
+		a /= 100
+	}
+	return math.Max(0, math.Min(1, a)), nil
+}
+
+// hue parses an angle, returning degrees
+func hue(s string) (float64, error) {
+	units := []struct {
+		suffix string
+		scale  float64
+	}{{"deg", 1}, {"grad", 0.9}, {"rad", 180 / math.Pi}, {"turn", 360}}
+	scale := 1.0
+	for _, u := range units {
+		if strings.HasSuffix(s, u.suffix) {
+			s, scale = strings.TrimSuffix(s, u.suffix), u.scale
+			break
+		}
+	}
+	h, percent, err := number(s)
+	if err != nil || percent {
+		return 0, fmt.Errorf("bad hue %q", s)
+	}
+	return math.Mod(math.Mod(h*scale, 360)+360, 360), nil
+}
+
+// colorspace converts components in a predefined color space to sRGB (0-1)
+func colorspace(space string, v []string) (float64, float64, float64, error) {
+	p, err := components(v, 1, 1, 1)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	switch space {
+	case "srgb":
+		return p[0], p[1], p[2], nil
+	case "srgb-linear":
+		return gamma(p[0]), gamma(p[1]), gamma(p[2]), nil
+	case "display-p3":
+		x, y, z := mul3(p3toxyz, linear(p[0]), linear(p[1]), linear(p[2]))
+		r, g, b := mul3(xyztosrgb, x, y, z)
+		return gamma(r), gamma(g), gamma(b), nil
+	case "xyz", "xyz-d65":
+		r, g, b := mul3(xyztosrgb, p[0], p[1], p[2])
+		return gamma(r), gamma(g), gamma(b), nil
+	case "xyz-d50":
+		x, y, z := mul3(d50tod65, p[0], p[1], p[2])
+		r, g, b := mul3(xyztosrgb, x, y, z)
+		return gamma(r), gamma(g), gamma(b), nil
+	}
+	return 0, 0, 0, fmt.Errorf("unknown color space %q", space)
+}
+
+// nrgba makes a color from sRGB and alpha values (0-1)
+func nrgba(r, g, b, a float64) color.NRGBA {
+	return color.NRGBA{clamp8(r), clamp8(g), clamp8(b), clamp8(a)}
+}
+
+// clamp8 converts a value (0-1) to 0-255, clipping out of range values
+func clamp8(v float64) uint8 {
+	if math.IsNaN(v) {
+		return 0
+	}
+	return uint8(math.Round(math.Max(0, math.Min(1, v)) * 255))
+}
+
+// Color space conversions.
+// Matrices and constants from https://www.w3.org/TR/css-color-4/#color-conversion-code
+
+type matrix3 [3][3]float64
+
+var (
+	xyztosrgb = matrix3{
+		{3.2409699419045226, -1.537383177570094, -0.4986107602930034},
+		{-0.9692436362808796, 1.8759675015077202, 0.04155505740717559},
+		{0.05563007969699366, -0.20397695888897652, 1.0569715142428786},
+	}
+	p3toxyz = matrix3{
+		{0.4865709486482162, 0.26566769316909306, 0.1982172852343625},
+		{0.2289745640697488, 0.6917385218365064, 0.079286914093745},
+		{0, 0.04511338185890264, 1.043944368900976},
+	}
+	d50tod65 = matrix3{
+		{0.955473421488075, -0.02309845494876471, 0.06325924320057072},
+		{-0.0283697093338637, 1.0099953980813041, 0.021041441191917323},
+		{0.012314014864481998, -0.020507649298898964, 1.330365926242124},
+	}
+)
+
+// mul3 multiplies a 3x3 matrix by a vector
+func mul3(m matrix3, x, y, z float64) (float64, float64, float64) {
+	return m[0][0]*x + m[0][1]*y + m[0][2]*z,
+		m[1][0]*x + m[1][1]*y + m[1][2]*z,
+		m[2][0]*x + m[2][1]*y + m[2][2]*z
+}
+
+// linear converts a gamma encoded sRGB value to linear light
+func linear(v float64) float64 {
+	a := math.Abs(v)
+	if a <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Copysign(math.Pow((a+0.055)/1.055, 2.4), v)
+}
+
+// gamma converts a linear light value to gamma encoded sRGB
+func gamma(v float64) float64 {
+	a := math.Abs(v)
+	if a <= 0.0031308 {
+		return v * 12.92
+	}
+	return math.Copysign(1.055*math.Pow(a, 1/2.4)-0.055, v)
+}
+
+// hsl2rgb converts hue (degrees), saturation and lightness (0-1) to sRGB
+func hsl2rgb(h, s, l float64) (float64, float64, float64) {
+	f := func(n float64) float64 {
+		k := math.Mod(n+h/30, 12)
+		a := s * math.Min(l, 1-l)
+		return l - a*math.Max(-1, math.Min(math.Min(k-3, 9-k), 1))
+	}
+	return f(0), f(8), f(4)
+}
+
+// hwb2rgb converts hue (degrees), whiteness and blackness (0-1) to sRGB
+func hwb2rgb(h, w, b float64) (float64, float64, float64) {
+	if w+b >= 1 {
+		gray := w / (w + b)
+		return gray, gray, gray
+	}
+	r, g, bl := hsl2rgb(h, 1, 0.5)
+	k := 1 - w - b
+	return r*k + w, g*k + w, bl*k + w
+}
+
+// lab2rgb converts CIE Lab (D50) to sRGB
+func lab2rgb(l, a, b float64) (float64, float64, float64) {
+	const e = 216.0 / 24389
+	const k = 24389.0 / 27
+	fy := (l + 16) / 116
+	fx := fy + a/500
+	fz := fy - b/200
+	x, y, z := (116*fx-16)/k, l/k, (116*fz-16)/k
+	if fx*fx*fx > e {
+		x = fx * fx * fx
+	}
+	if l > k*e {
+		y = fy * fy * fy
+	}
+	if fz*fz*fz > e {
+		z = fz * fz * fz
+	}
+	x, y, z = mul3(d50tod65, x*0.3457/0.3585, y, z*(1-0.3457-0.3585)/0.3585)
+	r, g, bl := mul3(xyztosrgb, x, y, z)
+	return gamma(r), gamma(g), gamma(bl)
+}
+
+// oklab2rgb converts OKLab to sRGB
+func oklab2rgb(l, a, b float64) (float64, float64, float64) {
+	lp := l + 0.3963377774*a + 0.2158037573*b
+	mp := l - 0.1055613458*a - 0.0638541728*b
+	sp := l - 0.0894841775*a - 1.2914855480*b
+	lp, mp, sp = lp*lp*lp, mp*mp*mp, sp*sp*sp
+	r := 4.0767416621*lp - 3.3077115913*mp + 0.2309699292*sp
+	g := -1.2684380046*lp + 2.6097574011*mp - 0.3413193965*sp
+	bl := -0.0041960863*lp - 0.7034186147*mp + 1.7076147010*sp
+	return gamma(r), gamma(g), gamma(bl)
+}
@@ -0,0 +1,226 @@
+package giocanvas
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"gioui.org/f32"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+)
+
+// Pattern fills. Patterns are anchored at the canvas origin, so that
+// the marks of neighboring shapes line up.
+
+// PatternKind is the design of a pattern
+type PatternKind int
+
+const (
+	Stripes    PatternKind = iota // parallel lines
+	Crosshatch                    // two sets of lines at right angles
+	Dots                          // a grid of dots
+	Checker                       // alternating squares
+	ImageTile                     // a repeated image
+)
+
+// Pattern is a fill made of repeated marks, used in place of a solid color
+type Pattern struct {
+	Kind       PatternKind
+	Color      color.NRGBA // color of the marks
+	Background color.NRGBA // color behind the marks; transparent by default
+	Spacing    float32     // distance between lines or dots, or the size of squares and image tiles (default 2)
+	Width      float32     // line width or dot diameter (default: a quarter, or for dots half, of the spacing)
+	Angle      float64     // rotation (radians)
+	Image      image.Image // the tile of an ImageTile pattern
+}
+
+// patternFrame maps pattern coordinates (s, t) to pixels: s runs along u, t along v
+type patternFrame struct {
+	u, v f32.Point
+}
+
+// at returns the pixel location of (s, t)
+func (f patternFrame) at(s, t float32) f32.Point {
+	return f.u.Mul(s).Add(f.v.Mul(t))
+}
+
+// span returns the range of pattern coordinates covering the box from min to max, in steps of size
+func (f patternFrame) span(min, max f32.Point, size float32) (s0, s1, t0, t1 float32) {
+	s0, t0 = float32(math.Inf(1)), float32(math.Inf(1))
+	s1, t1 = float32(math.Inf(-1)), float32(math.Inf(-1))
+	for _, p := range []f32.Point{min, max, {X: min.X, Y: max.Y}, {X: max.X, Y: min.Y}} {
+		s, t := p.X*f.u.X+p.Y*f.u.Y, p.X*f.v.X+p.Y*f.v.Y
+		s0, s1, t0, t1 = min32(s0, s), max32(s1, s), min32(t0, t), max32(t1, t)
+	}
+	floor := func(v float32) float32 { return float32(math.Floor(float64(v/size))) * size }
+	return floor(s0) - size, s1 + size, floor(t0) - size, t1 + size
+}
+
+// quad adds the parallelogram with corner (s, t) and sides ds and dt to the path
+func (f patternFrame) quad(path *clip.Path, s, t, ds, dt float32) {
+	path.MoveTo(f.at(s, t))
+	path.LineTo(f.at(s+ds, t))
+	path.LineTo(f.at(s+ds, t+dt))
+	path.LineTo(f.at(s, t+dt))
+	path.Close()
+}
+
+// absPatternFill fills the shape in spec, whose bounds are min to max, with a pattern
+func (c *Canvas) absPatternFill(spec clip.PathSpec, min, max f32.Point, p Pattern) {
+	ops := c.Context.Ops
+	stack := clip.Outline{Path: spec}.Op().Push(ops)
+	defer stack.Pop()
+	if p.Background.A > 0 {
+		paint.ColorOp{Color: p.Background}.Add(ops)
+		paint.PaintOp{}.Add(ops)
+	}
+	spacing := pct(p.Spacing, c.Width)
+	if spacing <= 0 {
+		spacing = pct(2, c.Width)
+	}
+	width := pct(p.Width, c.Width)
+	if width <= 0 {
+		width = spacing / 4
+		if p.Kind == Dots {
+			width = spacing / 2
+		}
+	}
+	sin, cos := math.Sincos(p.Angle)
+	f := patternFrame{u: f32.Pt(float32(cos), float32(-sin)), v: f32.Pt(float32(sin), float32(cos))}
+	if p.Kind == ImageTile {
+		c.imageTiles(f, min, max, p.Image, p.Spacing, p.Angle)
+		return
+	}
+	s0, s1, t0, t1 := f.span(min, max, spacing)
+	path := new(clip.Path)
+	path.Begin(ops)
+	switch p.Kind {
+	case Stripes, Crosshatch:
+		for t := t0; t <= t1; t += spacing {
+			f.quad(path, s0, t-width/2, s1-s0, width)
+		}
+		if p.Kind == Crosshatch {
+			for s := s0; s <= s1; s += spacing {
+				f.quad(path, s-width/2, t0, width, t1-t0)
+			}
+		}
+	case Dots:
+		for t := t0; t <= t1; t += spacing {
+			for s := s0; s <= s1; s += spacing {
+				o := f.at(s, t)
+				addCircle(path, o.X, o.Y, width/2)
+			}
+		}
+	case Checker:
+		for i, t := int(math.Round(float64(t0/spacing))), t0; t <= t1; i, t = i+1, t+spacing {
+			for j, s := int(math.Round(float64(s0/spacing))), s0; s <= s1; j, s = j+1, s+spacing {
+				if (i+j)%2 == 0 {
+					f.quad(path, s, t, spacing, spacing)
+				}
+			}
+		}
+	}
+	paint.FillShape(ops, p.Color, clip.Outline{Path: path.End()}.Op())
+}
+
+// imageTiles repeats an image, scaled to a width of size percent (natural size if zero), over the box from min to max
+func (c *Canvas) imageTiles(f patternFrame, min, max f32.Point, im image.Image, size float32, angle float64) {
+	if im == nil || im.Bounds().Dx() == 0 || im.Bounds().Dy() == 0 {
+		return
+	}
+	ops := c.Context.Ops
+	b := im.Bounds()
+	scale := float32(1)
+	if size > 0 {
+		scale = pct(size, c.Width) / float32(b.Dx())
+	}
+	tw, th := float32(b.Dx())*scale, float32(b.Dy())*scale
+	s0, s1, _, _ := f.span(min, max, tw)
+	_, _, t0, t1 := f.span(min, max, th)
+	imgop := paint.NewImageOp(im)
+	for t := t0; t <= t1; t += th {
+		for s := s0; s <= s1; s += tw {
+			tr := f32.Affine2D{}.Offset(f32.Pt(-float32(b.Min.X), -float32(b.Min.Y))).
+				Scale(f32.Point{}, f32.Pt(scale, scale)).
+				Rotate(f32.Point{}, float32(-angle)).
+				Offset(f.at(s, t))
+			stack := op.Affine(tr).Push(ops)
+			cl := clip.Rect{Min: b.Min, Max: b.Max}.Push(ops)
+			imgop.Add(ops)
+			paint.PaintOp{}.Add(ops)
+			cl.Pop()
+			stack.Pop()
+		}
+	}
+}
+
+// AbsPatternRect fills a rectangle with a pattern; upper left corner at (x, y), with dimensions (w, h)
+func (c *Canvas) AbsPatternRect(x, y, w, h float32, p Pattern) {
+	px := []float32{x, x + w, x + w, x}
+	py := []float32{y, y, y + h, y + h}
+	c.AbsPatternPolygon(px, py, p)
+}
+
+// AbsPatternPolygon fills a polygon with vertices in x and y with a pattern
+func (c *Canvas) AbsPatternPolygon(x, y []float32, p Pattern) {
+	if len(x) != len(y) || len(x) == 0 {
+		return
+	}
+	min, max := f32.Pt(x[0], y[0]), f32.Pt(x[0], y[0])
+	for i := range x {
+		min = f32.Pt(min32(min.X, x[i]), min32(min.Y, y[i]))
+		max = f32.Pt(max32(max.X, x[i]), max32(max.Y, y[i]))
+	}
+	c.absPatternFill(c.polygonPath(x, y), min, max, p)
+}
+
+// AbsPatternCircle fills a circle centered at (x, y) with a pattern
+func (c *Canvas) AbsPatternCircle(x, y, radius float32, p Pattern) {
+	c.absPatternFill(c.circlePath(x, y, radius), f32.Pt(x-radius, y-radius), f32.Pt(x+radius, y+radius), p)
+}
+
+// AbsPatternArc fills a circular arc centered at (x, y), through angles start and end (radians), with a pattern
+func (c *Canvas) AbsPatternArc(x, y, radius float32, start, end float64, p Pattern) {
+	c.absPatternFill(c.arcPath(x, y, radius, start, end), f32.Pt(x-radius, y-radius), f32.Pt(x+radius, y+radius), p)
+}
+
+// PatternRect fills a rectangle with a pattern, using percentage-based measures,
+// centered at (x, y), sized at (w, h)
+func (c *Canvas) PatternRect(x, y, w, h float32, p Pattern) {
+	c.PatternCornerRect(x-(w/2), y+(h/2), w, h, p)
+}
+
+// PatternCornerRect fills a rectangle with a pattern, using percentage-based measures,
+// upper left corner at (x, y), sized at (w, h)
+func (c *Canvas) PatternCornerRect(x, y, w, h float32, p Pattern) {
+	x, y = dimen(x, y, c.Width, c.Height)
+	c.AbsPatternRect(x, y, pct(w, c.Width), pct(h, c.Height), p)
+}
+
+// PatternPolygon fills a polygon with a pattern, using percentage-based measures
+func (c *Canvas) PatternPolygon(x, y []float32, p Pattern) {
+	if len(x) != len(y) || len(x) < 3 {
+		return
+	}
+	nx, ny := make([]float32, len(x)), make([]float32, len(y))
+	for i := range x {
+		nx[i], ny[i] = dimen(x[i], y[i], c.Width, c.Height)
+	}
+	c.AbsPatternPolygon(nx, ny, p)
+}
+
+// PatternCircle fills a circle with a pattern, using percentage-based measures,
+// center is (x, y), radius r
+func (c *Canvas) PatternCircle(x, y, r float32, p Pattern) {
+	x, y = dimen(x, y, c.Width, c.Height)
+	c.AbsPatternCircle(x, y, pct(r, c.Width), p)
+}
+
+// PatternArc fills an arc with a pattern, using percentage-based measures,
+// center is (x, y), the arc begins at angle a1, and ends at a2, with radius r
+func (c *Canvas) PatternArc(x, y, r float32, a1, a2 float64, p Pattern) {
+	x, y = dimen(x, y, c.Width, c.Height)
+	c.AbsPatternArc(x, y, pct(r, c.Width), a1, a2, p)
+}
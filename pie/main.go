@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"image/color"
 	"io"
+	"math"
 	"os"
 	"strconv"
 	"strings"
@@ -101,15 +102,35 @@ func piechart(canvas *giocanvas.Canvas, x, y, r float32, data []piedata) {
 		a2 := a1 + angle
 		mid := fullcircle - (a1 + (a2-a1)/2)
 		canvas.Arc(x, y, r, a1, a2, color)
+		label := fmt.Sprintf("%s (%.2f%%)", d.name, p*100)
+		if curved {
+			curvedlabel(canvas, x, y, r+ts/2, mid, ts, label, color)
+			a1 = a2
+			continue
+		}
 		tx, ty := canvas.Polar(x, y, labelr, float32(mid))
 		lx, ly := canvas.Polar(x, y, labelr-ts, float32(mid))
-		canvas.CText(tx, ty, ts, fmt.Sprintf("%s (%.2f%%)", d.name, p*100), color)
+		canvas.CText(tx, ty, ts, label, color)
 		canvas.Line(x, y, lx, ly, 0.1, color)
 		a1 = a2
 	}
 }
 
+// curvedlabel sets a label along the circle of radius r, centered at angle a:
+// clockwise over the top half, and counterclockwise under the bottom, so that it reads from left to right
+func curvedlabel(canvas *giocanvas.Canvas, x, y, r float32, a float64, size float32, label string, labelcolor color.NRGBA) {
+	const quarter = fullcircle / 4
+	style := giocanvas.TextPathStyle{Offset: r * quarter, Align: giocanvas.AlignCenter}
+	path := canvas.ArcPath(x, y, r, a+quarter, a-quarter)
+	if math.Sin(a) < 0 {
+		path = canvas.ArcPath(x, y, r, a-quarter, a+quarter)
+		style.Shift = -size * 0.8 // keep the text outside the circle
+	}
+	canvas.TextOnPath(path, size, label, labelcolor, style)
+}
+
 var pressed bool
+var curved bool
 var pieNumber int
 
 func kbpointer(q input.Source, context *op.Ops, ns int) {
@@ -267,6 +288,7 @@ func main() {
 
 	flag.IntVar(&cw, "width", 1000, "canvas width")
 	flag.IntVar(&ch, "height", 1000, "canvas height")
+	flag.BoolVar(&curved, "curved", false, "set the labels along the circle")
 	flag.Parse()
 
 	width := float32(cw)
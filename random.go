@@ -0,0 +1,319 @@
+package giocanvas
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Randomness for generative programs. A Rand made with the same seed
+// produces the same sequence, so that a drawing can be reproduced.
+
+// Rand is a seeded source of random numbers
+type Rand struct {
+	*rand.Rand
+	seed int64
+}
+
+// NewRand makes a source of random numbers from a seed; a zero seed uses the time
+func NewRand(seed int64) *Rand {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &Rand{Rand: rand.New(rand.NewSource(seed)), seed: seed}
+}
+
+// Seed returns the seed of r
+func (r *Rand) Seed() int64 {
+	return r.seed
+}
+
+// Range returns a number in [min, max)
+func (r *Rand) Range(min, max float64) float64 {
+	return min + r.Float64()*(max-min)
+}
+
+// Range32 returns a number in [min, max), as used by Canvas methods
+func (r *Rand) Range32(min, max float32) float32 {
+	return min + r.Float32()*(max-min)
+}
+
+// Normal returns a normally distributed number with the mean and standard deviation
+func (r *Rand) Normal(mean, stddev float64) float64 {
+	return mean + r.NormFloat64()*stddev
+}
+
+// Chance returns true with probability p
+func (r *Rand) Chance(p float64) bool {
+	return r.Float64() < p
+}
+
+// Choice returns a random element of s, or its zero value if s is empty
+func Choice[T any](r *Rand, s []T) T {
+	var zero T
+	if len(s) == 0 {
+		return zero
+	}
+	return s[r.Intn(len(s))]
+}
+
+// Shuffle puts the elements of s in random order
+func Shuffle[T any](r *Rand, s []T) {
+	r.Rand.Shuffle(len(s), func(i, j int) { s[i], s[j] = s[j], s[i] })
+}
+
+// Noise makes smoothly varying random values: Perlin, simplex and value noise.
+// The results are in the range -1 to 1, and the noise repeats every 256 units.
+type Noise struct {
+	perm   [512]uint8
+	values [256]float64
+}
+
+// NewNoise makes noise from a seed; a zero seed uses the time
+func NewNoise(seed int64) *Noise {
+	return NewRand(seed).Noise()
+}
+
+// Noise makes noise using the random numbers of r
+func (r *Rand) Noise() *Noise {
+	n := new(Noise)
+	for i, p := range r.Perm(256) {
+		n.perm[i], n.perm[i+256] = uint8(p), uint8(p)
+	}
+	for i := range n.values {
+		n.values[i] = r.Float64()*2 - 1
+	}
+	return n
+}
+
+// fade is the Perlin smoothing curve 6t^5 - 15t^4 + 10t^3
+func fade(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+// lattice returns the integer cell of v, wrapped to 0-255, and the position within it
+func lattice(v float64) (int, float64) {
+	f := math.Floor(v)
+	return int(f) & 255, v - f
+}
+
+// grad1 returns the dot product of a 1D gradient chosen by hash with x
+func grad1(hash uint8, x float64) float64 {
+	g := float64(hash&7) + 1 // 1 to 8
+	if hash&8 != 0 {
+		g = -g
+	}
+	return g * x / 8
+}
+
+// grad2 returns the dot product of a 2D gradient chosen by hash with (x, y)
+func grad2(hash uint8, x, y float64) float64 {
+	switch hash & 7 {
+	case 0:
+		return x + y
+	case 1:
+		return -x + y
+	case 2:
+		return x - y
+	case 3:
+		return -x - y
+	case 4:
+		return x
+	case 5:
+		return -x
+	case 6:
+		return y
+	}
+	return -y
+}
+
+// grad3 returns the dot product of a 3D gradient chosen by hash with (x, y, z)
+func grad3(hash uint8, x, y, z float64) float64 {
+	h := hash & 15
+	u, v := y, z
+	if h < 8 {
+		u = x
+	}
+	if h < 4 {
+		v = y
+	} else if h == 12 || h == 14 {
+		v = x
+	}
+	if h&1 != 0 {
+		u = -u
+	}
+	if h&2 != 0 {
+		v = -v
+	}
+	return u + v
+}
+
+// Perlin1 returns 1D Perlin noise at x
+func (n *Noise) Perlin1(x float64) float64 {
+	xi, xf := lattice(x)
+	p := n.perm
+	return 2 * lerp(grad1(p[xi], xf), grad1(p[xi+1], xf-1), fade(xf))
+}
+
+// Perlin2 returns 2D Perlin noise at (x, y)
+func (n *Noise) Perlin2(x, y float64) float64 {
+	xi, xf := lattice(x)
+	yi, yf := lattice(y)
+	p := n.perm
+	a, b := int(p[xi])+yi, int(p[xi+1])+yi
+	u, v := fade(xf), fade(yf)
+	return lerp(
+		lerp(grad2(p[a], xf, yf), grad2(p[b], xf-1, yf), u),
+		lerp(grad2(p[a+1], xf, yf-1), grad2(p[b+1], xf-1, yf-1), u),
+		v)
+}
+
+// Perlin3 returns 3D Perlin noise at (x, y, z)
+func (n *Noise) Perlin3(x, y, z float64) float64 {
+	xi, xf := lattice(x)
+	yi, yf := lattice(y)
+	zi, zf := lattice(z)
+	p := n.perm
+	a := int(p[xi]) + yi
+	aa, ab := int(p[a])+zi, int(p[a+1])+zi
+	b := int(p[xi+1]) + yi
+	ba, bb := int(p[b])+zi, int(p[b+1])+zi
+	u, v, w := fade(xf), fade(yf), fade(zf)
+	return lerp(
+		lerp(
+			lerp(grad3(p[aa], xf, yf, zf), grad3(p[ba], xf-1, yf, zf), u),
+			lerp(grad3(p[ab], xf, yf-1, zf), grad3(p[bb], xf-1, yf-1, zf), u), v),
+		lerp(
+			lerp(grad3(p[aa+1], xf, yf, zf-1), grad3(p[ba+1], xf-1, yf, zf-1), u),
+			lerp(grad3(p[ab+1], xf, yf-1, zf-1), grad3(p[bb+1], xf-1, yf-1, zf-1), u), v),
+		w)
+}
+
+// Value1 returns 1D value noise at x: random values at the integers, smoothly interpolated
+func (n *Noise) Value1(x float64) float64 {
+	xi, xf := lattice(x)
+	return lerp(n.value(xi), n.value(xi+1), fade(xf))
+}
+
+// Value2 returns 2D value noise at (x, y)
+func (n *Noise) Value2(x, y float64) float64 {
+	xi, xf := lattice(x)
+	yi, yf := lattice(y)
+	p := n.perm
+	u := fade(xf)
+	return lerp(
+		lerp(n.value(int(p[xi])+yi), n.value(int(p[xi+1])+yi), u),
+		lerp(n.value(int(p[xi])+yi+1), n.value(int(p[xi+1])+yi+1), u),
+		fade(yf))
+}
+
+// Value3 returns 3D value noise at (x, y, z)
+func (n *Noise) Value3(x, y, z float64) float64 {
+	xi, xf := lattice(x)
+	yi, yf := lattice(y)
+	zi, zf := lattice(z)
+	p := n.perm
+	corner := func(i, j, k int) float64 {
+		return n.value(int(p[int(p[xi+i])+yi+j]) + zi + k)
+	}
+	u, v := fade(xf), fade(yf)
+	plane := func(k int) float64 {
+		return lerp(lerp(corner(0, 0, k), corner(1, 0, k), u), lerp(corner(0, 1, k), corner(1, 1, k), u), v)
+	}
+	return lerp(plane(0), plane(1), fade(zf))
+}
+
+// value returns the random value at a hashed lattice point
+func (n *Noise) value(i int) float64 {
+	return n.values[n.perm[i&511]]
+}
+
+// Simplex noise, after Stefan Gustavson's "Simplex noise demystified"
+const (
+	skew2   = 0.36602540378443865 // (sqrt(3)-1)/2
+	unskew2 = 0.21132486540518713 // (3-sqrt(3))/6
+	skew3   = 1.0 / 3
+	unskew3 = 1.0 / 6
+)
+
+// Simplex1 returns 1D simplex noise at x
+func (n *Noise) Simplex1(x float64) float64 {
+	xi, x0 := lattice(x)
+	x1 := x0 - 1
+	t0, t1 := 1-x0*x0, 1-x1*x1
+	t0 *= t0
+	t1 *= t1
+	return 0.395 * 8 * (t0*t0*grad1(n.perm[xi], x0) + t1*t1*grad1(n.perm[xi+1], x1))
+}
+
+// Simplex2 returns 2D simplex noise at (x, y)
+func (n *Noise) Simplex2(x, y float64) float64 {
+	s := (x + y) * skew2
+	i, j := math.Floor(x+s), math.Floor(y+s)
+	t := (i + j) * unskew2
+	x0, y0 := x-(i-t), y-(j-t)
+	i1, j1 := 0, 1 // the middle corner of the simplex
+	if x0 > y0 {
+		i1, j1 = 1, 0
+	}
+	x1, y1 := x0-float64(i1)+unskew2, y0-float64(j1)+unskew2
+	x2, y2 := x0-1+2*unskew2, y0-1+2*unskew2
+	ii, jj := int(i)&255, int(j)&255
+	p := n.perm
+	corner := func(hash uint8, x, y float64) float64 {
+		t := 0.5 - x*x - y*y
+		if t < 0 {
+			return 0
+		}
+		t *= t
+		return t * t * grad2(hash, x, y)
+	}
+	return 70 * (corner(p[ii+int(p[jj])], x0, y0) +
+		corner(p[ii+i1+int(p[jj+j1])], x1, y1) +
+		corner(p[ii+1+int(p[jj+1])], x2, y2))
+}
+
+// Simplex3 returns 3D simplex noise at (x, y, z)
+func (n *Noise) Simplex3(x, y, z float64) float64 {
+	s := (x + y + z) * skew3
+	i, j, k := math.Floor(x+s), math.Floor(y+s), math.Floor(z+s)
+	t := (i + j + k) * unskew3
+	x0, y0, z0 := x-(i-t), y-(j-t), z-(k-t)
+
+	// the second and third corners of the simplex
+	var i1, j1, k1, i2, j2, k2 int
+	switch {
+	case x0 >= y0 && y0 >= z0:
+		i1, j1, k1, i2, j2, k2 = 1, 0, 0, 1, 1, 0
+	case x0 >= y0 && x0 >= z0:
+		i1, j1, k1, i2, j2, k2 = 1, 0, 0, 1, 0, 1
+	case x0 >= y0:
+		i1, j1, k1, i2, j2, k2 = 0, 0, 1, 1, 0, 1
+	case y0 < z0:
+		i1, j1, k1, i2, j2, k2 = 0, 0, 1, 0, 1, 1
+	case x0 < z0:
+		i1, j1, k1, i2, j2, k2 = 0, 1, 0, 0, 1, 1
+	default:
+		i1, j1, k1, i2, j2, k2 = 0, 1, 0, 1, 1, 0
+	}
+	x1, y1, z1 := x0-float64(i1)+unskew3, y0-float64(j1)+unskew3, z0-float64(k1)+unskew3
+	x2, y2, z2 := x0-float64(i2)+2*unskew3, y0-float64(j2)+2*unskew3, z0-float64(k2)+2*unskew3
+	x3, y3, z3 := x0-1+3*unskew3, y0-1+3*unskew3, z0-1+3*unskew3
+	ii, jj, kk := int(i)&255, int(j)&255, int(k)&255
+	p := n.perm
+	hash := func(a, b, c int) uint8 {
+		return p[ii+a+int(p[jj+b+int(p[kk+c])])]
+	}
+	corner := func(hash uint8, x, y, z float64) float64 {
+		t := 0.6 - x*x - y*y - z*z
+		if t < 0 {
+			return 0
+		}
+		t *= t
+		return t * t * grad3(hash, x, y, z)
+	}
+	return 32 * (corner(hash(0, 0, 0), x0, y0, z0) +
+		corner(hash(i1, j1, k1), x1, y1, z1) +
+		corner(hash(i2, j2, k2), x2, y2, z2) +
+		corner(hash(1, 1, 1), x3, y3, z3))
+}
@@ -47,6 +47,10 @@ func rgb(w *app.Window) error {
 		"#aabb",
 		"#aabbcc",
 		"#aabbcc64",
+		"#abc",
+		"rgba(100,50,2,0.5)",
+		"hsl(30 100% 50%)",
+		"oklch(70% 0.15 50 / 50%)",
 		"rgb()",
 		"hsv()",
 		"#",
@@ -63,9 +67,9 @@ func rgb(w *app.Window) error {
 			canvas := giocanvas.NewCanvas(float32(e.Size.X), float32(e.Size.Y), app.FrameEvent{})
 			x, y = 50, 95
 			for _, c := range colortab {
-				canvas.EText(x-10, y, 3, c, color.NRGBA{0, 0, 0, 255})
-				canvas.Circle(x, y+1, 2, giocanvas.ColorLookup(c))
-				y -= 6
+				canvas.EText(x-10, y, 2.5, c, color.NRGBA{0, 0, 0, 255})
+				canvas.Circle(x, y+1, 1.5, giocanvas.ColorLookup(c))
+				y -= 4.5
 			}
 			e.Frame(canvas.Context.Ops)
 		}
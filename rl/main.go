@@ -3,37 +3,37 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"image/color"
-	"math/rand"
 	"os"
-	"time"
 
 	"gioui.org/app"
-	"gioui.org/io/system"
 	"gioui.org/unit"
 	gc "github.com/ajstarks/giocanvas"
 )
 
-func rn(n int) float32 {
-	return float32(rand.Intn(n))
+func rn(r *gc.Rand, n int) float32 {
+	return float32(r.Intn(n))
 }
 
-func rl(title string, w, h, nlines int, thickness float32) {
+func rl(title string, w, h, nlines int, thickness float32, seed int64) {
 	width, height := float32(w), float32(h)
-	win := app.NewWindow(app.Title(title), app.Size(unit.Px(width), unit.Px(height)))
+	win := &app.Window{}
+	win.Option(app.Title(title), app.Size(unit.Dp(width), unit.Dp(height)))
 	for {
-		e := <-win.Events()
+		e := win.Event()
 		switch e := e.(type) {
-		case system.DestroyEvent:
+		case app.DestroyEvent:
 			os.Exit(0)
 
-		case system.FrameEvent:
+		case app.FrameEvent:
 			canvas := gc.NewCanvas(width, height, e)
 			canvas.Background(gc.ColorLookup("black"))
+			rnd := gc.NewRand(seed)
 			for i := 0; i < nlines; i++ {
-				r := uint8(rand.Intn(230))
+				r := uint8(rnd.Intn(230))
 				c := color.NRGBA{r, r, r, 150}
-				canvas.Line(rn(100), 0, rn(100), 100, thickness, c)
+				canvas.Line(rn(rnd, 100), 0, rn(rnd, 100), 100, thickness, c)
 			}
 			e.Frame(canvas.Context.Ops)
 		}
@@ -43,13 +43,15 @@ func rl(title string, w, h, nlines int, thickness float32) {
 func main() {
 	var w, h, n int
 	var size float64
+	var seed int64
 	flag.IntVar(&w, "width", 1000, "canvas width")
 	flag.IntVar(&h, "height", 1000, "canvas height")
 	flag.IntVar(&n, "n", 500, "number of lines")
 	flag.Float64Var(&size, "size", 2, "line thickness (%)")
-
+	flag.Int64Var(&seed, "seed", 0, "random seed (0: use the time)")
 	flag.Parse()
-	rand.Seed(time.Now().Unix())
-	go rl("Random Lines", w, h, n, float32(size))
+	seed = gc.NewRand(seed).Seed()
+	fmt.Fprintf(os.Stderr, "seed: %d\n", seed)
+	go rl("Random Lines", w, h, n, float32(size), seed)
 	app.Main()
 }
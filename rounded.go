@@ -0,0 +1,119 @@
+package giocanvas
+
+import (
+	"image/color"
+
+	"gioui.org/f32"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+)
+
+// Rounded rectangles. Corner radii are in pixels for the Abs forms, and
+// percentages of the canvas width otherwise, so that corners are circular.
+
+// kappa is the control point distance of a cubic bezier approximating a quarter circle
+const kappa = 0.551915024494
+
+// Corners are the radii of a rectangle's corners
+type Corners struct {
+	UpperLeft, UpperRight, LowerRight, LowerLeft float32
+}
+
+// Radius returns the same radius for all corners
+func Radius(r float32) Corners {
+	return Corners{r, r, r, r}
+}
+
+// scale returns the corners multiplied by f
+func (r Corners) scale(f float32) Corners {
+	return Corners{r.UpperLeft * f, r.UpperRight * f, r.LowerRight * f, r.LowerLeft * f}
+}
+
+// fit shrinks the radii proportionally, so that adjacent corners do not overlap in a w x h rectangle
+func (r Corners) fit(w, h float32) Corners {
+	f := float32(1)
+	for _, s := range [][3]float32{
+		{w, r.UpperLeft, r.UpperRight},
+		{w, r.LowerLeft, r.LowerRight},
+		{h, r.UpperLeft, r.LowerLeft},
+		{h, r.UpperRight, r.LowerRight},
+	} {
+		if sum := s[1] + s[2]; sum > s[0] && sum > 0 {
+			f = min32(f, s[0]/sum)
+		}
+	}
+	return r.scale(f)
+}
+
+// roundedRect makes the path of a rectangle with upper left corner at (x, y), size (w, h)
+func (c *Canvas) roundedRect(x, y, w, h float32, r Corners) clip.PathSpec {
+	r = r.fit(w, h)
+	path := new(clip.Path)
+	path.Begin(c.Context.Ops)
+	corner := func(from, at, to f32.Point) {
+		path.LineTo(from)
+		path.CubeTo(from.Add(at.Sub(from).Mul(kappa)), to.Add(at.Sub(to).Mul(kappa)), to)
+	}
+	right, bottom := x+w, y+h
+	path.MoveTo(f32.Pt(x+r.UpperLeft, y))
+	corner(f32.Pt(right-r.UpperRight, y), f32.Pt(right, y), f32.Pt(right, y+r.UpperRight))
+	corner(f32.Pt(right, bottom-r.LowerRight), f32.Pt(right, bottom), f32.Pt(right-r.LowerRight, bottom))
+	corner(f32.Pt(x+r.LowerLeft, bottom), f32.Pt(x, bottom), f32.Pt(x, bottom-r.LowerLeft))
+	corner(f32.Pt(x, y+r.UpperLeft), f32.Pt(x, y), f32.Pt(x+r.UpperLeft, y))
+	path.Close()
+	return path.End()
+}
+
+// AbsRoundedRect makes a filled rectangle with rounded corners; upper left corner at (x, y), with dimensions (w, h)
+func (c *Canvas) AbsRoundedRect(x, y, w, h float32, r Corners, fillcolor color.NRGBA) {
+	paint.FillShape(c.Context.Ops, fillcolor, clip.Outline{Path: c.roundedRect(x, y, w, h, r)}.Op())
+}
+
+// AbsCenterRoundedRect makes a filled rectangle with rounded corners centered at (x, y), with dimensions (w, h)
+func (c *Canvas) AbsCenterRoundedRect(x, y, w, h float32, r Corners, fillcolor color.NRGBA) {
+	c.AbsRoundedRect(x-(w/2), y-(h/2), w, h, r, fillcolor)
+}
+
+// AbsStrokedRoundedRect makes an outlined rectangle with rounded corners; upper left corner at (x, y),
+// with dimensions (w, h), using line width size
+func (c *Canvas) AbsStrokedRoundedRect(x, y, w, h float32, r Corners, size float32, strokecolor color.NRGBA) {
+	paint.FillShape(c.Context.Ops, strokecolor, clip.Stroke{Path: c.roundedRect(x, y, w, h, r), Width: size}.Op())
+}
+
+// AbsStrokedCenterRoundedRect makes an outlined rectangle with rounded corners centered at (x, y),
+// with dimensions (w, h), using line width size
+func (c *Canvas) AbsStrokedCenterRoundedRect(x, y, w, h float32, r Corners, size float32, strokecolor color.NRGBA) {
+	c.AbsStrokedRoundedRect(x-(w/2), y-(h/2), w, h, r, size, strokecolor)
+}
+
+// absRounded converts a percentage-based rectangle with upper left corner at (x, y) to pixels
+func (c *Canvas) absRounded(x, y, w, h float32, r Corners) (float32, float32, float32, float32, Corners) {
+	x, y = dimen(x, y, c.Width, c.Height)
+	return x, y, pct(w, c.Width), pct(h, c.Height), r.scale(c.Width / 100)
+}
+
+// RoundedRect makes a filled rectangle with rounded corners using percentage-based measures,
+// upper left corner at (x, y), sized at (w, h)
+func (c *Canvas) RoundedRect(x, y, w, h float32, r Corners, fillcolor color.NRGBA) {
+	ax, ay, aw, ah, ar := c.absRounded(x, y, w, h, r)
+	c.AbsRoundedRect(ax, ay, aw, ah, ar, fillcolor)
+}
+
+// CenterRoundedRect makes a filled rectangle with rounded corners using percentage-based measures,
+// with center at (x, y), sized at (w, h)
+func (c *Canvas) CenterRoundedRect(x, y, w, h float32, r Corners, fillcolor color.NRGBA) {
+	c.RoundedRect(x-(w/2), y+(h/2), w, h, r, fillcolor)
+}
+
+// StrokedRoundedRect makes an outlined rectangle with rounded corners using percentage-based measures,
+// upper left corner at (x, y), sized at (w, h), using line width size
+func (c *Canvas) StrokedRoundedRect(x, y, w, h float32, r Corners, size float32, strokecolor color.NRGBA) {
+	ax, ay, aw, ah, ar := c.absRounded(x, y, w, h, r)
+	c.AbsStrokedRoundedRect(ax, ay, aw, ah, ar, pct(size, c.Width), strokecolor)
+}
+
+// StrokedCenterRoundedRect makes an outlined rectangle with rounded corners using percentage-based measures,
+// with center at (x, y), sized at (w, h), using line width size
+func (c *Canvas) StrokedCenterRoundedRect(x, y, w, h float32, r Corners, size float32, strokecolor color.NRGBA) {
+	c.StrokedRoundedRect(x-(w/2), y+(h/2), w, h, r, size, strokecolor)
+}
@@ -0,0 +1,404 @@
+package giocanvas
+
+import (
+	"image"
+
+	"gioui.org/op"
+)
+
+// A retained scene graph. A Scene is a tree of nodes: groups, shapes, text and images,
+// kept between frames and drawn with the Canvas methods. Nodes may be changed between
+// frames; each group keeps the drawing of its children, and only groups containing
+// changes are redrawn. Coordinates and sizes are percentage-based.
+
+// Node is an element of a scene: a *Group, *Shape, *TextNode or *ImageNode
+type Node interface {
+	base() *node
+	draw(c *Canvas)
+}
+
+// node holds what is common to every node: its ID, transformation, style and visibility
+type node struct {
+	ID        string // identifies the node for Scene.Find
+	parent    *Group
+	transform Transform
+	style     *Style
+	hidden    bool
+}
+
+func (n *node) base() *node {
+	return n
+}
+
+// changed records that the node's drawing has changed, so that its group is redrawn
+func (n *node) changed() {
+	if n.parent != nil {
+		n.parent.invalidate()
+	}
+}
+
+// Parent returns the group containing the node, or nil
+func (n *node) Parent() *Group {
+	return n.parent
+}
+
+// Transform returns the transformation of the node
+func (n *node) Transform() Transform {
+	return n.transform
+}
+
+// SetTransform sets the transformation of the node, relative to its group
+func (n *node) SetTransform(t Transform) {
+	n.transform = t
+	n.changed()
+}
+
+// Visible reports whether the node is drawn
+func (n *node) Visible() bool {
+	return !n.hidden
+}
+
+// SetVisible shows or hides the node
+func (n *node) SetVisible(visible bool) {
+	if n.hidden == !visible {
+		return
+	}
+	n.hidden = !visible
+	n.changed()
+}
+
+// Style returns the style of the node; ok is false if the node uses the style of its group
+func (n *node) Style() (style Style, ok bool) {
+	if n.style == nil {
+		return Style{}, false
+	}
+	return *n.style, true
+}
+
+// SetStyle sets the style of the node
+func (n *node) SetStyle(s Style) {
+	n.style = &s
+	n.changed()
+}
+
+// InheritStyle makes the node use the style of its group
+func (n *node) InheritStyle() {
+	n.style = nil
+	n.changed()
+}
+
+// drawNode draws a node with its style and transformation, if it is visible
+func (c *Canvas) drawNode(n Node) {
+	b := n.base()
+	if b.hidden {
+		return
+	}
+	c.Push()
+	defer c.Pop()
+	if b.style != nil {
+		c.Style = *b.style
+	}
+	if b.transform != (Transform{}) {
+		c.PushTransform(b.transform)
+	}
+	n.draw(c)
+}
+
+// Group is a node containing other nodes, drawn in order.
+// The drawing of a group is kept, and redrawn only if the group has changed.
+type Group struct {
+	node
+	children []Node
+	ops      op.Ops
+	call     op.CallOp
+	dirty    bool
+}
+
+// NewGroup makes an empty group
+func NewGroup(nodes ...Node) *Group {
+	g := &Group{dirty: true}
+	g.Add(nodes...)
+	return g
+}
+
+// invalidate marks the group, and the groups containing it, to be redrawn
+func (g *Group) invalidate() {
+	for ; g != nil && !g.dirty; g = g.parent {
+		g.dirty = true
+	}
+}
+
+// invalidateAll marks the group and every group within it to be redrawn
+func (g *Group) invalidateAll() {
+	g.invalidate()
+	for _, n := range g.children {
+		if child, ok := n.(*Group); ok {
+			child.invalidateAll()
+		}
+	}
+}
+
+// SetStyle sets the style of the group, used by the nodes within it that do not have their own
+func (g *Group) SetStyle(s Style) {
+	g.node.SetStyle(s)
+	g.invalidateAll()
+}
+
+// InheritStyle makes the group use the style of its group
+func (g *Group) InheritStyle() {
+	g.node.InheritStyle()
+	g.invalidateAll()
+}
+
+// Children returns the nodes in the group
+func (g *Group) Children() []Node {
+	return g.children
+}
+
+// Add appends nodes to the group, removing them from their previous groups
+func (g *Group) Add(nodes ...Node) {
+	for _, n := range nodes {
+		if p := n.base().parent; p != nil {
+			p.Remove(n)
+		}
+		n.base().parent = g
+		g.children = append(g.children, n)
+	}
+	g.invalidate()
+}
+
+// Remove takes a node out of the group
+func (g *Group) Remove(n Node) {
+	for i, child := range g.children {
+		if child == n {
+			g.children = append(g.children[:i], g.children[i+1:]...)
+			n.base().parent = nil
+			g.invalidate()
+			return
+		}
+	}
+}
+
+// Find returns the node with the ID in the group, including the group itself, or nil
+func (g *Group) Find(id string) Node {
+	if g.ID == id {
+		return g
+	}
+	for _, n := range g.children {
+		if child, ok := n.(*Group); ok {
+			if found := child.Find(id); found != nil {
+				return found
+			}
+		} else if n.base().ID == id {
+			return n
+		}
+	}
+	return nil
+}
+
+// draw redraws the group's children if it has changed, and adds its drawing to the canvas
+func (g *Group) draw(c *Canvas) {
+	if g.dirty {
+		g.ops.Reset()
+		ops := c.Context.Ops
+		c.Context.Ops = &g.ops
+		macro := op.Record(&g.ops)
+		for _, n := range g.children {
+			c.drawNode(n)
+		}
+		g.call = macro.Stop()
+		c.Context.Ops = ops
+		g.dirty = false
+	}
+	g.call.Add(c.Context.Ops)
+}
+
+// ShapeKind is the kind of a Shape node
+type ShapeKind int
+
+const (
+	RectShape    ShapeKind = iota // filled rectangle, centered at (x, y), sized (w, h)
+	CircleShape                   // filled circle, centered at (x, y), radius w
+	EllipseShape                  // filled ellipse, centered at (x, y), radii (w, h)
+	PolygonShape                  // filled polygon
+	LineShape                     // line from (x, y) to (w, h), using the stroke color and width
+)
+
+// Shape is a node drawing a shape, using the fill color, or the stroke for lines
+type Shape struct {
+	node
+	kind       ShapeKind
+	x, y, w, h float32
+	polygon    Polygon
+}
+
+// NewRectShape makes a rectangle node, centered at (x, y), sized (w, h)
+func NewRectShape(x, y, w, h float32) *Shape {
+	return &Shape{kind: RectShape, x: x, y: y, w: w, h: h}
+}
+
+// NewCircleShape makes a circle node, centered at (x, y), with radius r
+func NewCircleShape(x, y, r float32) *Shape {
+	return &Shape{kind: CircleShape, x: x, y: y, w: r}
+}
+
+// NewEllipseShape makes an ellipse node, centered at (x, y), with radii (w, h)
+func NewEllipseShape(x, y, w, h float32) *Shape {
+	return &Shape{kind: EllipseShape, x: x, y: y, w: w, h: h}
+}
+
+// NewPolygonShape makes a polygon node
+func NewPolygonShape(p Polygon) *Shape {
+	return &Shape{kind: PolygonShape, polygon: p}
+}
+
+// NewLineShape makes a line node from (x0, y0) to (x1, y1)
+func NewLineShape(x0, y0, x1, y1 float32) *Shape {
+	return &Shape{kind: LineShape, x: x0, y: y0, w: x1, h: y1}
+}
+
+// Kind returns the kind of shape
+func (s *Shape) Kind() ShapeKind {
+	return s.kind
+}
+
+// Geometry returns the location and size of the shape (see ShapeKind)
+func (s *Shape) Geometry() (x, y, w, h float32) {
+	return s.x, s.y, s.w, s.h
+}
+
+// SetGeometry changes the location and size of the shape (see ShapeKind)
+func (s *Shape) SetGeometry(x, y, w, h float32) {
+	s.x, s.y, s.w, s.h = x, y, w, h
+	s.changed()
+}
+
+// SetPolygon changes the vertices of a polygon shape
+func (s *Shape) SetPolygon(p Polygon) {
+	s.polygon = p
+	s.changed()
+}
+
+func (s *Shape) draw(c *Canvas) {
+	switch s.kind {
+	case RectShape:
+		c.DrawRect(s.x, s.y, s.w, s.h)
+	case CircleShape:
+		c.DrawCircle(s.x, s.y, s.w)
+	case EllipseShape:
+		c.DrawEllipse(s.x, s.y, s.w, s.h)
+	case PolygonShape:
+		x, y := make([]float32, len(s.polygon)), make([]float32, len(s.polygon))
+		for i, p := range s.polygon {
+			x[i], y[i] = p.X, p.Y
+		}
+		c.DrawPolygon(x, y)
+	case LineShape:
+		c.DrawLine(s.x, s.y, s.w, s.h)
+	}
+}
+
+// TextNode is a node placing text at (x, y), using the fill color, font, text size and alignment
+type TextNode struct {
+	node
+	x, y float32
+	s    string
+}
+
+// NewTextNode makes a text node
+func NewTextNode(x, y float32, s string) *TextNode {
+	return &TextNode{x: x, y: y, s: s}
+}
+
+// String returns the text
+func (t *TextNode) String() string {
+	return t.s
+}
+
+// SetText changes the text
+func (t *TextNode) SetText(s string) {
+	if s == t.s {
+		return
+	}
+	t.s = s
+	t.changed()
+}
+
+// Position returns the location of the text
+func (t *TextNode) Position() (float32, float32) {
+	return t.x, t.y
+}
+
+// SetPosition moves the text to (x, y)
+func (t *TextNode) SetPosition(x, y float32) {
+	t.x, t.y = x, y
+	t.changed()
+}
+
+func (t *TextNode) draw(c *Canvas) {
+	c.DrawText(t.x, t.y, t.s)
+}
+
+// ImageNode is a node placing an image centered at (x, y), scaled by a percentage
+type ImageNode struct {
+	node
+	im    image.Image
+	x, y  float32
+	scale float32
+}
+
+// NewImageNode makes an image node
+func NewImageNode(im image.Image, x, y, scale float32) *ImageNode {
+	return &ImageNode{im: im, x: x, y: y, scale: scale}
+}
+
+// SetImage changes the image
+func (i *ImageNode) SetImage(im image.Image) {
+	i.im = im
+	i.changed()
+}
+
+// SetPosition moves the image to (x, y), scaled by scale
+func (i *ImageNode) SetPosition(x, y, scale float32) {
+	i.x, i.y, i.scale = x, y, scale
+	i.changed()
+}
+
+func (i *ImageNode) draw(c *Canvas) {
+	if i.im == nil {
+		return
+	}
+	b := i.im.Bounds()
+	c.Img(i.im, i.x, i.y, b.Dx(), b.Dy(), i.scale)
+}
+
+// Scene is a retained scene graph
+type Scene struct {
+	Root          *Group
+	width, height float32
+}
+
+// NewScene makes a scene containing nodes
+func NewScene(nodes ...Node) *Scene {
+	return &Scene{Root: NewGroup(nodes...)}
+}
+
+// Find returns the node with the ID, or nil
+func (s *Scene) Find(id string) Node {
+	return s.Root.Find(id)
+}
+
+// Invalidate marks the whole scene to be redrawn
+func (s *Scene) Invalidate() {
+	s.Root.invalidateAll()
+}
+
+// Render draws the scene on the canvas, redrawing only the groups that have changed.
+// The whole scene is redrawn if the canvas has changed size.
+func (c *Canvas) Render(s *Scene) {
+	if c.Width != s.width || c.Height != s.height {
+		s.width, s.height = c.Width, c.Height
+		s.Invalidate()
+	}
+	c.drawNode(s.Root)
+}
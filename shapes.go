@@ -0,0 +1,191 @@
+package giocanvas
+
+import (
+	"image/color"
+	"math"
+
+	"gioui.org/f32"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+)
+
+// Shapes made from polygons. Radii are percentages of the canvas width,
+// with vertices compensated for the canvas aspect ratio, as with Polar.
+// Rotations are counterclockwise (radians); at zero rotation, the first vertex is at the top.
+
+// AbsStrokedPolygon makes an outlined polygon with vertices in x and y, using line width size
+func (c *Canvas) AbsStrokedPolygon(x, y []float32, size float32, strokecolor color.NRGBA) {
+	if len(x) != len(y) || len(x) == 0 {
+		return
+	}
+	path := new(clip.Path)
+	ops := c.Context.Ops
+	path.Begin(ops)
+	path.MoveTo(f32.Point{X: x[0], Y: y[0]})
+	for i := 1; i < len(x); i++ {
+		path.LineTo(f32.Point{X: x[i], Y: y[i]})
+	}
+	path.Close()
+	stack := clip.Stroke{Path: path.End(), Width: size}.Op().Push(ops)
+	paint.Fill(ops, strokecolor)
+	stack.Pop()
+}
+
+// StrokedPolygon makes an outlined polygon with vertices in x and y, using percentage-based measures
+func (c *Canvas) StrokedPolygon(x, y []float32, size float32, strokecolor color.NRGBA) {
+	if len(x) != len(y) {
+		return
+	}
+	px, py := make([]float32, len(x)), make([]float32, len(y))
+	for i := range x {
+		px[i], py[i] = dimen(x[i], y[i], c.Width, c.Height)
+	}
+	c.AbsStrokedPolygon(px, py, pct(size, c.Width), strokecolor)
+}
+
+// regular returns the vertices of a regular polygon with n sides centered at (x, y), radius r
+func (c *Canvas) regular(x, y float32, n int, r float32, rotation float64) ([]float32, []float32) {
+	px, py := make([]float32, n), make([]float32, n)
+	for i := 0; i < n; i++ {
+		a := rotation + math.Pi/2 + 2*math.Pi*float64(i)/float64(n)
+		px[i], py[i] = c.Polar(x, y, r, float32(a))
+	}
+	return px, py
+}
+
+// RegularPolygon makes a filled regular polygon with n sides centered at (x, y),
+// radius (center to vertex) r, rotated by rotation (radians)
+func (c *Canvas) RegularPolygon(x, y float32, n int, r float32, rotation float64, fillcolor color.NRGBA) {
+	if n < 3 {
+		return
+	}
+	px, py := c.regular(x, y, n, r, rotation)
+	c.Polygon(px, py, fillcolor)
+}
+
+// StrokedRegularPolygon makes an outlined regular polygon with n sides centered at (x, y),
+// radius (center to vertex) r, rotated by rotation (radians), using line width size
+func (c *Canvas) StrokedRegularPolygon(x, y float32, n int, r float32, rotation float64, size float32, strokecolor color.NRGBA) {
+	if n < 3 {
+		return
+	}
+	px, py := c.regular(x, y, n, r, rotation)
+	c.StrokedPolygon(px, py, size, strokecolor)
+}
+
+// star returns the vertices of a star centered at (x, y)
+func (c *Canvas) star(x, y float32, points int, inner, outer float32) ([]float32, []float32) {
+	n := points * 2
+	px, py := make([]float32, n), make([]float32, n)
+	for i := 0; i < n; i++ {
+		r := outer
+		if i%2 == 1 {
+			r = inner
+		}
+		a := math.Pi/2 + math.Pi*float64(i)/float64(points)
+		px[i], py[i] = c.Polar(x, y, r, float32(a))
+	}
+	return px, py
+}
+
+// Star makes a filled star centered at (x, y), with the number of points,
+// and inner and outer radii. The first point is at the top.
+func (c *Canvas) Star(x, y float32, points int, inner, outer float32, fillcolor color.NRGBA) {
+	if points < 2 {
+		return
+	}
+	px, py := c.star(x, y, points, inner, outer)
+	c.Polygon(px, py, fillcolor)
+}
+
+// StrokedStar makes an outlined star centered at (x, y), with the number of points,
+// and inner and outer radii, using line width size
+func (c *Canvas) StrokedStar(x, y float32, points int, inner, outer, size float32, strokecolor color.NRGBA) {
+	if points < 2 {
+		return
+	}
+	px, py := c.star(x, y, points, inner, outer)
+	c.StrokedPolygon(px, py, size, strokecolor)
+}
+
+// superellipse returns the vertices of a superellipse centered at (x, y)
+func (c *Canvas) superellipse(x, y, w, h, n float32) ([]float32, []float32) {
+	const steps = 120
+	aspect := float64(c.Width / c.Height)
+	e := 2 / float64(n)
+	px, py := make([]float32, steps), make([]float32, steps)
+	for i := 0; i < steps; i++ {
+		sin, cos := math.Sincos(2 * math.Pi * float64(i) / steps)
+		dx := float64(w) * math.Copysign(math.Pow(math.Abs(cos), e), cos)
+		dy := float64(h) * aspect * math.Copysign(math.Pow(math.Abs(sin), e), sin)
+		px[i], py[i] = x+float32(dx), y+float32(dy)
+	}
+	return px, py
+}
+
+// Superellipse makes a filled superellipse (|x/w|^n + |y/h|^n = 1) centered at (x, y), radii (w, h).
+// n=2 is an ellipse, larger values approach a rectangle, and smaller values are pinched.
+func (c *Canvas) Superellipse(x, y, w, h, n float32, fillcolor color.NRGBA) {
+	if n <= 0 {
+		return
+	}
+	px, py := c.superellipse(x, y, w, h, n)
+	c.Polygon(px, py, fillcolor)
+}
+
+// StrokedSuperellipse makes an outlined superellipse centered at (x, y), radii (w, h), using line width size
+func (c *Canvas) StrokedSuperellipse(x, y, w, h, n, size float32, strokecolor color.NRGBA) {
+	if n <= 0 {
+		return
+	}
+	px, py := c.superellipse(x, y, w, h, n)
+	c.StrokedPolygon(px, py, size, strokecolor)
+}
+
+// Squircle makes a filled squircle (a superellipse with n=4) centered at (x, y), radius r
+func (c *Canvas) Squircle(x, y, r float32, fillcolor color.NRGBA) {
+	c.Superellipse(x, y, r, r, 4, fillcolor)
+}
+
+// StrokedSquircle makes an outlined squircle centered at (x, y), radius r, using line width size
+func (c *Canvas) StrokedSquircle(x, y, r, size float32, strokecolor color.NRGBA) {
+	c.StrokedSuperellipse(x, y, r, r, 4, size, strokecolor)
+}
+
+// HexTiles returns the hexagons of radius r (pointing up) that tile the area with upper left
+// corner at (x, y), size (w, h). Alternate rows are offset by half a hexagon.
+func (c *Canvas) HexTiles(x, y, w, h, r float32) []Polygon {
+	aspect := c.Width / c.Height
+	dx := r * float32(math.Sqrt(3))
+	dy := r * 1.5 * aspect
+	var tiles []Polygon
+	for row, cy := 0, y-r*aspect; cy >= y-h-r*aspect/2; row, cy = row+1, cy-dy {
+		cx := x + dx/2
+		if row%2 == 1 {
+			cx = x
+		}
+		for ; cx <= x+w+dx/2; cx += dx {
+			tiles = append(tiles, NewPolygon(c.regular(cx, cy, 6, r, 0)))
+		}
+	}
+	return tiles
+}
+
+// TriangleTiles returns the equilateral triangles with sides of length side, alternately
+// pointing up and down, that tile the area with upper left corner at (x, y), size (w, h)
+func (c *Canvas) TriangleTiles(x, y, w, h, side float32) []Polygon {
+	th := side * float32(math.Sqrt(3)) / 2 * c.Width / c.Height // triangle height, in percent of the canvas height
+	var tiles []Polygon
+	for row, top := 0, y; top-th >= y-h-th/2; row, top = row+1, top-th {
+		bottom := top - th
+		for i, left := 0, x-side/2; left+side/2 <= x+w; i, left = i+1, left+side/2 {
+			up := (i+row)%2 == 0
+			if up {
+				tiles = append(tiles, Polygon{{left, bottom}, {left + side, bottom}, {left + side/2, top}})
+			} else {
+				tiles = append(tiles, Polygon{{left, top}, {left + side/2, bottom}, {left + side, top}})
+			}
+		}
+	}
+	return tiles
+}
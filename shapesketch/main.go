@@ -134,17 +134,10 @@ func deckspec(prec int) {
 		sw := float32(math.Abs(dx) * 2)
 		puts(shape + ftoa(bx, prec) + ftoa(by, prec) + ftoa(sw, prec) + "\n")
 	case "circle":
-		puts("circle" + ftoa(bx, prec) + ftoa(by, prec) + ftoa(dist(bx, by, cx, cy), prec) + "\n")
+		puts("circle" + ftoa(bx, prec) + ftoa(by, prec) + ftoa(giocanvas.Pt(bx, by).Dist(giocanvas.Pt(cx, cy)), prec) + "\n")
 	}
 }
 
-// dist computes the distance between (x1, y1) and (x2, y2)
-func dist(x1, y1, x2, y2 float32) float32 {
-	x := float64(x2 - x1)
-	y := float64(y2 - y1)
-	return float32(math.Sqrt(x*x + y*y))
-}
-
 func rad2deg(r float32) float32 {
 	d := float32((180 / math.Pi) * r)
 	if d < 0 {
@@ -154,14 +147,9 @@ func rad2deg(r float32) float32 {
 }
 
 func arcElements() (float32, float64, float64) {
-	r := dist(bx, by, cx, cy)
-	dx1 := float64(ex - bx)
-	dy1 := float64(ey - by)
-	dx2 := float64(cx - bx)
-	dy2 := float64(cy - by)
-	a1 := math.Atan2(dy1, dx1)
-	a2 := math.Atan2(dy2, dx2)
-	return r, a1, a2
+	b := giocanvas.Pt(bx, by)
+	c := giocanvas.Pt(cx, cy)
+	return b.Dist(c), b.Angle(giocanvas.Pt(ex, ey)), b.Angle(c)
 }
 
 // pct returns the percentage of its input
@@ -312,7 +300,7 @@ func shapesketch(w *app.Window, cfg config) error {
 			case "circle":
 				textcoord(canvas, bx, by, begincolor, cfg)
 				textcoord(canvas, cx, cy, shapecolor, cfg)
-				canvas.Circle(bx, by, dist(bx, by, cx, cy), cfg.shapecolor)
+				canvas.Circle(bx, by, giocanvas.Pt(bx, by).Dist(giocanvas.Pt(cx, cy)), cfg.shapecolor)
 			case "square":
 				textcoord(canvas, bx, by, begincolor, cfg)
 				textcoord(canvas, cx, cy, shapecolor, cfg)
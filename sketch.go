@@ -0,0 +1,127 @@
+package giocanvas
+
+import (
+	"fmt"
+	"image/color"
+	"os"
+	"time"
+
+	"gioui.org/app"
+	"gioui.org/io/event"
+	"gioui.org/io/input"
+	"gioui.org/io/key"
+	"gioui.org/io/pointer"
+	"gioui.org/op"
+	"gioui.org/unit"
+)
+
+// Sketch describes a Processing-style program, run with Run.
+// Draw is called for every frame, the other callbacks are optional.
+type Sketch struct {
+	Title         string                              // window title
+	Width, Height float32                             // initial window size (default 1000x1000)
+	Background    color.NRGBA                         // canvas background, not drawn if transparent
+	FrameRate     float64                             // frames per second; zero redraws on input only
+	Options       []app.Option                        // additional window options
+	Setup         func()                              // called once, before the first frame
+	Draw          func(canvas *Canvas)                // called for every frame
+	Key           func(e key.Event)                   // called for key presses and releases
+	Pointer       func(x, y float32, e pointer.Event) // called for pointer events, (x, y) in percentage coordinates
+	Capture       *Capture                            // saves snapshots and recordings; nil uses the default files
+}
+
+// Run opens a window and runs the sketch.
+// The program exits when the window is closed, or the Escape key is pressed.
+// Ctrl+S saves a snapshot of the window, and Ctrl+R starts and stops recording (see Capture).
+func Run(s Sketch) {
+	go func() {
+		if err := s.run(new(app.Window)); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}()
+	app.Main()
+}
+
+// run is the sketch event loop
+func (s *Sketch) run(w *app.Window) error {
+	width, height := s.Width, s.Height
+	if width <= 0 {
+		width = 1000
+	}
+	if height <= 0 {
+		height = 1000
+	}
+	options := []app.Option{app.Title(s.Title), app.Size(unit.Dp(width), unit.Dp(height))}
+	w.Option(append(options, s.Options...)...)
+	if s.Capture == nil {
+		s.Capture = new(Capture)
+	}
+	defer func() {
+		if err := s.Capture.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+		}
+	}()
+	if s.Setup != nil {
+		s.Setup()
+	}
+	for {
+		switch e := w.Event().(type) {
+		case app.DestroyEvent:
+			return e.Err
+		case app.FrameEvent:
+			canvas := NewCanvas(float32(e.Size.X), float32(e.Size.Y), app.FrameEvent{})
+			if s.input(e.Source, canvas) {
+				return nil
+			}
+			if s.Background.A > 0 {
+				canvas.Background(s.Background)
+			}
+			if s.Draw != nil {
+				s.Draw(canvas)
+			}
+			event.Op(canvas.Context.Ops, s)
+			if err := s.Capture.Frame(canvas); err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+			}
+			if s.FrameRate > 0 {
+				next := e.Now.Add(time.Duration(float64(time.Second) / s.FrameRate))
+				e.Source.Execute(op.InvalidateCmd{At: next})
+			}
+			e.Frame(canvas.Context.Ops)
+		}
+	}
+}
+
+// input dispatches keyboard and pointer events to the sketch callbacks,
+// returning true if the sketch should quit
+func (s *Sketch) input(q input.Source, canvas *Canvas) bool {
+	for {
+		ev, ok := q.Event(
+			key.Filter{Optional: key.ModCtrl | key.ModShift | key.ModAlt | key.ModSuper},
+			pointer.Filter{Target: s, Kinds: pointer.Press | pointer.Release | pointer.Move | pointer.Drag},
+		)
+		if !ok {
+			return false
+		}
+		switch e := ev.(type) {
+		case key.Event:
+			if e.Name == key.NameEscape && e.State == key.Press {
+				return true
+			}
+			if s.Capture.Key(e) {
+				continue
+			}
+			if s.Key != nil {
+				s.Key(e)
+			}
+		case pointer.Event:
+			if s.Pointer != nil {
+				x := 100 * (e.Position.X / canvas.Width)
+				y := 100 - (100 * (e.Position.Y / canvas.Height))
+				s.Pointer(x, y, e)
+			}
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package giocanvas
+
+import (
+	"image"
+	"image/color"
+
+	"gioui.org/font"
+	"gioui.org/op"
+	"gioui.org/text"
+)
+
+// Style is the current drawing state of a Canvas, used by the Draw methods.
+// Sizes and widths are percentages of the canvas width.
+type Style struct {
+	FillColor   color.NRGBA    // fill for shapes and text
+	StrokeColor color.NRGBA    // color for lines, curves and arcs
+	StrokeWidth float32        // width for lines, curves and arcs
+	Font        font.Typeface  // text font, the theme font if empty
+	TextSize    float32        // text size
+	Align       text.Alignment // text alignment: text.Start, text.Middle, text.End
+}
+
+// DefaultStyle is the initial style of a new Canvas
+var DefaultStyle = Style{
+	FillColor:   color.NRGBA{0, 0, 0, 255},
+	StrokeColor: color.NRGBA{0, 0, 0, 255},
+	StrokeWidth: 0.2,
+	TextSize:    2,
+	Align:       text.Start,
+}
+
+// state is a saved style and the transformations made since it was saved
+type state struct {
+	style     Style
+	group     op.TransformStack
+	transform []op.TransformStack
+}
+
+// SetFill sets the fill color
+func (c *Canvas) SetFill(fillcolor color.NRGBA) {
+	c.Style.FillColor = fillcolor
+}
+
+// SetStroke sets the stroke color and width
+func (c *Canvas) SetStroke(strokecolor color.NRGBA, size float32) {
+	c.Style.StrokeColor = strokecolor
+	c.Style.StrokeWidth = size
+}
+
+// SetFont sets the text font (by typeface name) and size
+func (c *Canvas) SetFont(name string, size float32) {
+	c.Style.Font = font.Typeface(name)
+	c.Style.TextSize = size
+}
+
+// SetAlign sets the text alignment
+func (c *Canvas) SetAlign(alignment text.Alignment) {
+	c.Style.Align = alignment
+}
+
+// Push saves the current style, and begins a group of transformations.
+// Transformations made after Push are ended by the matching Pop,
+// unless they have already been ended.
+func (c *Canvas) Push() {
+	group := op.Offset(image.Point{}).Push(c.Context.Ops)
+	c.saved = append(c.saved, state{style: c.Style, group: group})
+}
+
+// Pop ends the transformations made since the matching Push, and restores the saved style
+func (c *Canvas) Pop() {
+	n := len(c.saved) - 1
+	if n < 0 {
+		return
+	}
+	s := c.saved[n]
+	for i := len(s.transform) - 1; i >= 0; i-- {
+		c.untrack(s.transform[i])
+		s.transform[i].Pop()
+	}
+	s.group.Pop()
+	c.Style = s.style
+	c.saved = c.saved[:n]
+}
+
+// keep records a transformation made inside a Push, to be ended by Pop
+func (c *Canvas) keep(stack op.TransformStack) {
+	if n := len(c.saved) - 1; n >= 0 {
+		c.saved[n].transform = append(c.saved[n].transform, stack)
+	}
+}
+
+// Methods using the current style, and percentage-based measures
+
+// DrawLine makes a line from (x0, y0) to (x1, y1) using the stroke color and width
+func (c *Canvas) DrawLine(x0, y0, x1, y1 float32) {
+	c.Line(x0, y0, x1, y1, c.Style.StrokeWidth, c.Style.StrokeColor)
+}
+
+// DrawCurve makes a stroked quadratic Bezier curve
+// starting at (x, y), control point at (cx, cy), end point (ex, ey)
+func (c *Canvas) DrawCurve(x, y, cx, cy, ex, ey float32) {
+	c.QuadStrokedCurve(x, y, cx, cy, ex, ey, c.Style.StrokeWidth, c.Style.StrokeColor)
+}
+
+// DrawCubeCurve makes a stroked cubic Bezier curve
+// starting at (x, y), control points at (cx1, cy1), (cx2, cy2), end point (ex, ey)
+func (c *Canvas) DrawCubeCurve(x, y, cx1, cy1, cx2, cy2, ex, ey float32) {
+	c.CubeStrokedCurve(x, y, cx1, cy1, cx2, cy2, ex, ey, c.Style.StrokeWidth, c.Style.StrokeColor)
+}
+
+// DrawArcLine makes a stroked arc centered at (x, y), radius r, from angle a1 to a2 (radians)
+func (c *Canvas) DrawArcLine(x, y, r float32, a1, a2 float64) {
+	c.ArcLine(x, y, r, a1, a2, c.Style.StrokeWidth, c.Style.StrokeColor)
+}
+
+// DrawRect makes a filled rectangle centered at (x, y), with size (w, h)
+func (c *Canvas) DrawRect(x, y, w, h float32) {
+	c.CenterRect(x, y, w, h, c.Style.FillColor)
+}
+
+// DrawCornerRect makes a filled rectangle with upper left corner at (x, y), with size (w, h)
+func (c *Canvas) DrawCornerRect(x, y, w, h float32) {
+	c.CornerRect(x, y, w, h, c.Style.FillColor)
+}
+
+// DrawSquare makes a filled square centered at (x, y), sides w
+func (c *Canvas) DrawSquare(x, y, w float32) {
+	c.Square(x, y, w, c.Style.FillColor)
+}
+
+// DrawCircle makes a filled circle centered at (x, y), radius r
+func (c *Canvas) DrawCircle(x, y, r float32) {
+	c.Circle(x, y, r, c.Style.FillColor)
+}
+
+// DrawEllipse makes a filled ellipse centered at (x, y), radii (w, h)
+func (c *Canvas) DrawEllipse(x, y, w, h float32) {
+	c.Ellipse(x, y, w, h, c.Style.FillColor)
+}
+
+// DrawArc makes a filled arc centered at (x, y), radius r, from angle a1 to a2 (radians)
+func (c *Canvas) DrawArc(x, y, r float32, a1, a2 float64) {
+	c.Arc(x, y, r, a1, a2, c.Style.FillColor)
+}
+
+// DrawPolygon makes a filled polygon with vertices in x and y
+func (c *Canvas) DrawPolygon(x, y []float32) {
+	c.Polygon(x, y, c.Style.FillColor)
+}
+
+// DrawText places text at (x, y), using the fill color, font, text size and alignment
+func (c *Canvas) DrawText(x, y float32, s string) {
+	defer c.useFont()()
+	x, y = dimen(x, y, c.Width, c.Height)
+	size := pct(c.Style.TextSize, c.Width)
+	c.textops(x, y, size, c.Style.Align, s, c.Style.FillColor)
+}
+
+// DrawVText places vertical text at (x, y), using the fill color, font, text size and alignment
+func (c *Canvas) DrawVText(x, y float32, s string, orient TextOrientation) {
+	defer c.useFont()()
+	x, y = dimen(x, y, c.Width, c.Height)
+	size := pct(c.Style.TextSize, c.Width)
+	c.vtextops(x, y, size, c.Style.Align, s, c.Style.FillColor, orient)
+}
+
+// DrawTextWrap places text beginning at (x, y), wrapped at width,
+// using the fill color, font and text size
+func (c *Canvas) DrawTextWrap(x, y, width float32, s string) {
+	defer c.useFont()()
+	c.TextWrap(x, y, c.Style.TextSize, width, s, c.Style.FillColor)
+}
+
+// DrawTextBox sets text in the box with its upper left corner at (x, y), and dimensions (w, h),
+// using the fill color, font and text size, reporting whether the text overflowed the box
+func (c *Canvas) DrawTextBox(x, y, w, h float32, s string, style TextBoxStyle) bool {
+	defer c.useFont()()
+	return c.TextBox(x, y, w, h, c.Style.TextSize, s, c.Style.FillColor, style)
+}
+
+// useFont sets the theme font from the style, returning a function that restores it
+func (c *Canvas) useFont() func() {
+	face := c.Theme.Face
+	if len(c.Style.Font) > 0 {
+		c.Theme.Face = c.Style.Font
+	}
+	return func() { c.Theme.Face = face }
+}
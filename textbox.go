@@ -0,0 +1,251 @@
+package giocanvas
+
+import (
+	"image/color"
+	"strings"
+	"unicode/utf8"
+
+	"gioui.org/font"
+	"gioui.org/text"
+	"gioui.org/unit"
+	"golang.org/x/image/math/fixed"
+)
+
+// Text boxes. TextBox sets text within a rectangle: wrapped at its width, aligned horizontally
+// and vertically, and kept within its height, by reducing the text size, or by ending the last
+// line that fits with an ellipsis. Lines in the text begin new paragraphs, each set in the
+// direction of the canvas, or its own: in right to left paragraphs, lines begin at the right.
+
+// TextAlign is the horizontal alignment of the lines in a text box
+type TextAlign int
+
+const (
+	AlignStart   TextAlign = iota // lines begin at the start of the box, the left for left to right text
+	AlignCenter                   // lines are centered
+	AlignEnd                      // lines end at the end of the box, the right for left to right text
+	AlignJustify                  // lines fill the width, except for the last line of a paragraph
+)
+
+// VerticalAlign is the vertical alignment of the lines in a text box
+type VerticalAlign int
+
+const (
+	AlignTop    VerticalAlign = iota // the first line is at the top of the box
+	AlignMiddle                      // the lines are centered
+	AlignBottom                      // the last line is at the bottom of the box
+)
+
+// TextBoxStyle describes how text is set in a box
+type TextBoxStyle struct {
+	Align       TextAlign     // horizontal alignment
+	VAlign      VerticalAlign // vertical alignment
+	LineSpacing float32       // distance between baselines, as a multiple of the text size (default 1.2)
+	MaxLines    int           // maximum number of lines; zero allows as many as fit
+	MinSize     float32       // if not zero, the text size is reduced until the text fits, down to MinSize
+}
+
+// boxLine is a line of text in a box
+type boxLine struct {
+	words  []string
+	widths []float32
+	width  float32 // the width of the words, with a space between each
+	last   bool    // the last line of a paragraph
+	rtl    bool    // the paragraph is right to left
+}
+
+// boxText is text set in a box
+type boxText struct {
+	lines           []boxLine
+	size            float32
+	ascent, descent float32
+	lead            float32 // the distance between baselines
+	overflow        bool    // lines were left out
+}
+
+// textWidth returns the width of s, and the ascent and descent of the font, set at size
+func (c *Canvas) textWidth(s string, size float32) (width, ascent, descent float32) {
+	c.Theme.Shaper.LayoutString(text.Parameters{
+		Font:     font.Font{Typeface: c.Theme.Face},
+		PxPerEm:  fixed.I(c.Context.Sp(unit.Sp(size))),
+		MaxWidth: 1 << 24,
+		Locale:   c.Context.Locale,
+	}, s)
+	var w fixed.Int26_6
+	for {
+		g, ok := c.Theme.Shaper.NextGlyph()
+		if !ok {
+			break
+		}
+		w += g.Advance
+		ascent, descent = fixed26(g.Ascent), fixed26(g.Descent)
+	}
+	return fixed26(w), ascent, descent
+}
+
+// fixed26 converts a fixed point number to float32
+func fixed26(v fixed.Int26_6) float32 {
+	return float32(v) / 64
+}
+
+// wrapText breaks the paragraphs of s into lines no wider than width.
+// Words wider than the width are broken between characters.
+func (c *Canvas) wrapText(s string, size, width float32) []boxLine {
+	space, _, _ := c.textWidth(" ", size)
+	var lines []boxLine
+	for _, para := range strings.Split(s, "\n") {
+		rtl := c.rtl(para)
+		line := boxLine{rtl: rtl}
+		add := func(word string, w float32) {
+			if len(line.words) > 0 && line.width+space+w > width {
+				lines = append(lines, line)
+				line = boxLine{rtl: rtl}
+			}
+			if len(line.words) > 0 {
+				line.width += space
+			}
+			line.words = append(line.words, word)
+			line.widths = append(line.widths, w)
+			line.width += w
+		}
+		for _, word := range strings.Fields(para) {
+			w, _, _ := c.textWidth(word, size)
+			for w > width && utf8.RuneCountInString(word) > 1 {
+				part := []rune(word)
+				n := len(part) - 1
+				for ; n > 1; n-- {
+					if pw, _, _ := c.textWidth(string(part[:n]), size); pw <= width {
+						break
+					}
+				}
+				pw, _, _ := c.textWidth(string(part[:n]), size)
+				add(string(part[:n]), pw)
+				word = string(part[n:])
+				w, _, _ = c.textWidth(word, size)
+			}
+			add(word, w)
+		}
+		line.last = true
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// setText sets s in a box of width w and height h, at size, or smaller if the style allows
+func (c *Canvas) setText(w, h, size float32, s string, style TextBoxStyle) boxText {
+	spacing := style.LineSpacing
+	if spacing <= 0 {
+		spacing = 1.2
+	}
+	set := func(size float32) boxText {
+		_, ascent, descent := c.textWidth("H", size)
+		t := boxText{lines: c.wrapText(s, size, w), size: size, ascent: ascent, descent: descent, lead: spacing * size}
+		n := 0 // the number of lines that fit
+		if h >= ascent+descent {
+			n = 1 + int((h-ascent-descent)/t.lead)
+		}
+		if style.MaxLines > 0 && style.MaxLines < n {
+			n = style.MaxLines
+		}
+		if len(t.lines) > n {
+			t.lines, t.overflow = t.lines[:n], true
+		}
+		return t
+	}
+	t := set(size)
+	if t.overflow && style.MinSize > 0 && style.MinSize < size {
+		lo, hi := style.MinSize, size
+		if t = set(lo); !t.overflow {
+			for i := 0; i < 10; i++ {
+				mid := (lo + hi) / 2
+				if m := set(mid); m.overflow {
+					hi = mid
+				} else {
+					lo, t = mid, m
+				}
+			}
+		}
+	}
+	if t.overflow && len(t.lines) > 0 {
+		t.lines[len(t.lines)-1] = c.ellipsis(t.lines[len(t.lines)-1], t.size, w)
+	}
+	return t
+}
+
+// ellipsis ends a line with an ellipsis, removing characters so that it fits the width
+func (c *Canvas) ellipsis(line boxLine, size, width float32) boxLine {
+	s := []rune(strings.Join(line.words, " "))
+	for {
+		e := strings.TrimRight(string(s), " ") + "…"
+		w, _, _ := c.textWidth(e, size)
+		if w <= width || len(s) == 0 {
+			return boxLine{words: []string{e}, widths: []float32{w}, width: w, last: true, rtl: line.rtl}
+		}
+		s = s[:len(s)-1]
+	}
+}
+
+// AbsTextBox sets text in the box with its upper left corner at (x, y), and dimensions (w, h),
+// reporting whether the text overflowed the box
+func (c *Canvas) AbsTextBox(x, y, w, h, size float32, s string, fillcolor color.NRGBA, style TextBoxStyle) bool {
+	t := c.setText(w, h, size, s, style)
+	n := len(t.lines)
+	if n == 0 {
+		return t.overflow
+	}
+	block := t.ascent + float32(n-1)*t.lead + t.descent
+	switch style.VAlign {
+	case AlignMiddle:
+		y += (h - block) / 2
+	case AlignBottom:
+		y += h - block
+	}
+	// textops places the top of the text size above its y coordinate, and the baseline ascent below the top
+	baseline := y + t.ascent
+	for _, line := range t.lines {
+		ty := baseline + t.size - t.ascent
+		switch {
+		case style.Align == AlignJustify && !line.last && len(line.words) > 1:
+			var words float32
+			for _, w := range line.widths {
+				words += w
+			}
+			gap := (w - words) / float32(len(line.words)-1)
+			wx := x
+			for _, i := range visualOrder(line.words, line.rtl) {
+				c.textdir(wx, ty, t.size, leftEdge(line.rtl), line.words[i], fillcolor, line.rtl)
+				wx += line.widths[i] + gap
+			}
+		default:
+			lx := x
+			switch end := w - line.width; {
+			case style.Align == AlignCenter:
+				lx += end / 2
+			case (style.Align == AlignEnd) != line.rtl: // right to left lines start at the right
+				lx += end
+			}
+			c.textdir(lx, ty, t.size, leftEdge(line.rtl), strings.Join(line.words, " "), fillcolor, line.rtl)
+		}
+		baseline += t.lead
+	}
+	return t.overflow
+}
+
+// leftEdge returns the alignment that places text with its left edge at a point
+func leftEdge(rtl bool) text.Alignment {
+	if rtl {
+		return text.End
+	}
+	return text.Start
+}
+
+// TextBox sets text in a box using percentage-based measures, with its upper left corner at (x, y),
+// and dimensions (w, h), at the specified size (and the style's MinSize) and color.
+// It reports whether the text overflowed the box.
+func (c *Canvas) TextBox(x, y, w, h, size float32, s string, fillcolor color.NRGBA, style TextBoxStyle) bool {
+	x, y = dimen(x, y, c.Width, c.Height)
+	w = pct(w, c.Width)
+	h = pct(h, c.Height)
+	size = pct(size, c.Width)
+	style.MinSize = pct(style.MinSize, c.Width)
+	return c.AbsTextBox(x, y, w, h, size, s, fillcolor, style)
+}
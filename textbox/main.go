@@ -0,0 +1,71 @@
+// textbox sets text in a box: move the pointer to change the box size, A and V change the alignment
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"gioui.org/io/key"
+	"gioui.org/io/pointer"
+	"github.com/ajstarks/giocanvas"
+)
+
+const quote = "If there is no struggle, there is no progress. Those who profess to favor freedom, and yet depreciate agitation, are men who want crops without plowing up the ground. They want rain without thunder and lightning. They want the ocean without the awful roar of its many waters.\nFrederick Douglass"
+
+func main() {
+	var size, minsize float64
+	var maxlines int
+	flag.Float64Var(&size, "size", 3, "text size")
+	flag.Float64Var(&minsize, "minsize", 1, "minimum text size, when shrinking to fit (0: do not shrink)")
+	flag.IntVar(&maxlines, "maxlines", 0, "maximum number of lines (0: as many as fit)")
+	flag.Parse()
+
+	aligns := []string{"start", "center", "end", "justify"}
+	valigns := []string{"top", "middle", "bottom"}
+	var align, valign int
+	var bw, bh float32 = 60, 40
+	boxcolor := giocanvas.ColorLookup("rgb(230,230,230)")
+	textcolor := giocanvas.ColorLookup("black")
+	labelcolor := giocanvas.ColorLookup("gray")
+
+	giocanvas.Run(giocanvas.Sketch{
+		Title:      "textbox: move the pointer to size the box, A and V change the alignment",
+		Background: giocanvas.ColorLookup("white"),
+		Draw: func(canvas *giocanvas.Canvas) {
+			x, y := 50-bw/2, 50+bh/2
+			canvas.Rect(50, 50, bw, bh, boxcolor)
+			overflow := canvas.TextBox(x, y, bw, bh, float32(size), quote, textcolor, giocanvas.TextBoxStyle{
+				Align:    giocanvas.TextAlign(align),
+				VAlign:   giocanvas.VerticalAlign(valign),
+				MaxLines: maxlines,
+				MinSize:  float32(minsize),
+			})
+			status := fmt.Sprintf("%.0f%% x %.0f%%, %s, %s", bw, bh, aligns[align], valigns[valign])
+			if overflow {
+				status += ", overflow"
+			}
+			canvas.TextMid(50, 5, 2, status, labelcolor)
+		},
+		Pointer: func(x, y float32, e pointer.Event) {
+			bw, bh = 2*abs(x-50), 2*abs(y-50)
+		},
+		Key: func(e key.Event) {
+			if e.State != key.Press {
+				return
+			}
+			switch e.Name {
+			case "A":
+				align = (align + 1) % len(aligns)
+			case "V":
+				valign = (valign + 1) % len(valigns)
+			}
+		},
+	})
+}
+
+func abs(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
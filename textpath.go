@@ -0,0 +1,298 @@
+package giocanvas
+
+import (
+	"image/color"
+	"math"
+	"sort"
+
+	"gioui.org/f32"
+	"gioui.org/font"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/text"
+	"gioui.org/unit"
+	"golang.org/x/image/math/fixed"
+)
+
+// Text along paths. A TextPath is a line, polyline, curve, arc or circle, made with the
+// path methods of a canvas. TextOnPath sets text along a path: each character is placed
+// on the path and turned to follow it, and may also be rotated and scaled on its own.
+
+// TextPath is a path for text, measured along its length
+type TextPath struct {
+	points []f32.Point // vertices, in pixels
+	dist   []float32   // the distance along the path to each vertex
+}
+
+// newTextPath makes a path through points, in pixels
+func newTextPath(points []f32.Point) *TextPath {
+	p := &TextPath{points: points, dist: make([]float32, len(points))}
+	for i := 1; i < len(points); i++ {
+		d := points[i].Sub(points[i-1])
+		p.dist[i] = p.dist[i-1] + float32(math.Hypot(float64(d.X), float64(d.Y)))
+	}
+	return p
+}
+
+// Length returns the length of the path, in pixels
+func (p *TextPath) Length() float32 {
+	if len(p.dist) == 0 {
+		return 0
+	}
+	return p.dist[len(p.dist)-1]
+}
+
+// at returns the point at distance d along the path, and the direction of the path there
+// (radians, on screen). Before its start and past its end, the path continues in a straight line.
+func (p *TextPath) at(d float32) (f32.Point, float32) {
+	n := len(p.points)
+	switch n {
+	case 0:
+		return f32.Point{}, 0
+	case 1:
+		return p.points[0], 0
+	}
+	i := sort.Search(n, func(i int) bool { return p.dist[i] >= d })
+	if i < 1 {
+		i = 1
+	}
+	if i > n-1 {
+		i = n - 1
+	}
+	a, b := p.points[i-1], p.points[i]
+	var t float32
+	if seg := p.dist[i] - p.dist[i-1]; seg > 0 {
+		t = (d - p.dist[i-1]) / seg
+	}
+	return a.Add(b.Sub(a).Mul(t)), float32(math.Atan2(float64(b.Y-a.Y), float64(b.X-a.X)))
+}
+
+// pathSteps returns the number of segments for a curve or arc of about length pixels
+func pathSteps(length float32) int {
+	const step = 4 // pixels per segment
+	n := int(length / step)
+	if n < 8 {
+		n = 8
+	}
+	return n
+}
+
+// LinePath makes a path from (x0, y0) to (x1, y1), using percentage-based measures
+func (c *Canvas) LinePath(x0, y0, x1, y1 float32) *TextPath {
+	return c.PolylinePath([]float32{x0, x1}, []float32{y0, y1})
+}
+
+// PolylinePath makes a path through the points in x and y, using percentage-based measures
+func (c *Canvas) PolylinePath(x, y []float32) *TextPath {
+	n := len(x)
+	if len(y) < n {
+		n = len(y)
+	}
+	points := make([]f32.Point, n)
+	for i := 0; i < n; i++ {
+		points[i].X, points[i].Y = dimen(x[i], y[i], c.Width, c.Height)
+	}
+	return newTextPath(points)
+}
+
+// QuadPath makes a quadratic Bézier curve path beginning at (x, y), with control point at (cx, cy),
+// ending at (ex, ey), using percentage-based measures
+func (c *Canvas) QuadPath(x, y, cx, cy, ex, ey float32) *TextPath {
+	p0, p1, p2 := c.point(x, y), c.point(cx, cy), c.point(ex, ey)
+	n := pathSteps(dist(p0, p1) + dist(p1, p2))
+	points := make([]f32.Point, n+1)
+	for i := range points {
+		t := float32(i) / float32(n)
+		u := 1 - t
+		points[i] = p0.Mul(u * u).Add(p1.Mul(2 * u * t)).Add(p2.Mul(t * t))
+	}
+	return newTextPath(points)
+}
+
+// CubePath makes a cubic Bézier curve path beginning at (x, y), with control points at (cx1, cy1)
+// and (cx2, cy2), ending at (ex, ey), using percentage-based measures
+func (c *Canvas) CubePath(x, y, cx1, cy1, cx2, cy2, ex, ey float32) *TextPath {
+	p0, p1, p2, p3 := c.point(x, y), c.point(cx1, cy1), c.point(cx2, cy2), c.point(ex, ey)
+	n := pathSteps(dist(p0, p1) + dist(p1, p2) + dist(p2, p3))
+	points := make([]f32.Point, n+1)
+	for i := range points {
+		t := float32(i) / float32(n)
+		u := 1 - t
+		points[i] = p0.Mul(u * u * u).Add(p1.Mul(3 * u * u * t)).Add(p2.Mul(3 * u * t * t)).Add(p3.Mul(t * t * t))
+	}
+	return newTextPath(points)
+}
+
+// ArcPath makes an arc path, using percentage-based measures, centered at (x, y), with radius r,
+// from angle a1 to a2 (radians, counterclockwise as with ArcLine). The path runs counterclockwise
+// if a2 is greater than a1, and clockwise otherwise: text on the top of a circle reads from left
+// to right along a clockwise path, and text on the bottom, along a counterclockwise one.
+func (c *Canvas) ArcPath(x, y, r float32, a1, a2 float64) *TextPath {
+	o := c.point(x, y)
+	rx := pct(r, c.Width)
+	n := pathSteps(rx * float32(math.Abs(a2-a1)))
+	points := make([]f32.Point, n+1)
+	for i := range points {
+		a := a1 + (a2-a1)*float64(i)/float64(n)
+		points[i] = o.Add(f32.Pt(rx*float32(math.Cos(a)), -rx*float32(math.Sin(a))))
+	}
+	return newTextPath(points)
+}
+
+// CirclePath makes a clockwise circular path, using percentage-based measures,
+// centered at (x, y), with radius r, beginning at angle a (radians)
+func (c *Canvas) CirclePath(x, y, r float32, a float64) *TextPath {
+	return c.ArcPath(x, y, r, a, a-2*math.Pi)
+}
+
+// point converts a point to pixels
+func (c *Canvas) point(x, y float32) f32.Point {
+	x, y = dimen(x, y, c.Width, c.Height)
+	return f32.Pt(x, y)
+}
+
+// dist returns the distance between two points
+func dist(p, q f32.Point) float32 {
+	d := q.Sub(p)
+	return float32(math.Hypot(float64(d.X), float64(d.Y)))
+}
+
+// TextPathStyle describes how text is set along a path
+type TextPathStyle struct {
+	Offset  float32   // distance along the path at which the text begins, is centered, or ends, by Align
+	Spacing float32   // space added between characters
+	Align   TextAlign // AlignStart, AlignCenter or AlignEnd place the text at Offset; AlignJustify spreads it from Offset to the end
+	Shift   float32   // distance of the baseline from the path, to the left of its direction (above a path running left to right)
+	// Glyph returns the rotation (radians, as with Rotate) and scale of the i-th character;
+	// nil leaves the characters upright on the path, at the text size. A zero scale is taken as 1.
+	Glyph func(i int) (angle, scale float32)
+}
+
+// pathCluster is a character of text set along a path: a cluster of shaped glyphs
+type pathCluster struct {
+	s       string       // the characters of the cluster
+	i       int          // its index in the text
+	glyphs  []text.Glyph // its glyphs, placed from the left of the cluster
+	x       float32      // the left of the cluster in the shaped text
+	advance float32
+}
+
+// textClusters returns the characters of s, shaped at size in the direction of the canvas,
+// from left to right
+func (c *Canvas) textClusters(s string, size float32) []pathCluster {
+	defer c.useDirection(c.rtl(s))()
+	c.Theme.Shaper.LayoutString(text.Parameters{
+		Font:     font.Font{Typeface: c.Theme.Face},
+		PxPerEm:  fixed.I(c.Context.Sp(unit.Sp(size))),
+		MaxWidth: 1 << 24,
+		Locale:   c.Context.Locale,
+	}, s)
+	// glyphs come in the order of the text; right to left runs go leftwards
+	runes := []rune(s)
+	var clusters []pathCluster
+	var glyphs []text.Glyph
+	for {
+		g, ok := c.Theme.Shaper.NextGlyph()
+		if !ok {
+			break
+		}
+		glyphs = append(glyphs, g)
+		if g.Flags&text.FlagClusterBreak == 0 || g.Runes == 0 {
+			continue
+		}
+		n := int(g.Runes)
+		if n > len(runes) {
+			n = len(runes)
+		}
+		left, right := glyphs[0].X, glyphs[0].X
+		for _, g := range glyphs {
+			if g.X < left {
+				left = g.X
+			}
+			if g.X+g.Advance > right {
+				right = g.X + g.Advance
+			}
+		}
+		for i := range glyphs {
+			glyphs[i].X -= left
+			glyphs[i].Y = 0
+		}
+		clusters = append(clusters, pathCluster{s: string(runes[:n]), i: len(clusters), glyphs: glyphs, x: fixed26(left), advance: fixed26(right - left)})
+		runes, glyphs = runes[n:], nil
+	}
+	sort.SliceStable(clusters, func(i, j int) bool { return clusters[i].x < clusters[j].x })
+	return clusters
+}
+
+// AbsTextOnPath sets text along a path, at the specified size and color;
+// the size and the distances in the style are in pixels. The characters follow the path
+// in the order they are seen, so right to left text reads from the end of the path toward its start.
+func (c *Canvas) AbsTextOnPath(p *TextPath, size float32, s string, fillcolor color.NRGBA, style TextPathStyle) {
+	clusters := c.textClusters(s, size)
+	n := len(clusters)
+	if n == 0 {
+		return
+	}
+	scales := make([]float32, n)
+	angles := make([]float32, n)
+	var width float32 // the width of the characters, without spacing
+	for k, cl := range clusters {
+		scales[k] = 1
+		if style.Glyph != nil {
+			angles[k], scales[k] = style.Glyph(cl.i)
+			if scales[k] == 0 {
+				scales[k] = 1
+			}
+		}
+		width += cl.advance * scales[k]
+	}
+	spacing := style.Spacing
+	d := style.Offset
+	switch style.Align {
+	case AlignCenter:
+		d -= (width + spacing*float32(n-1)) / 2
+	case AlignEnd:
+		d -= width + spacing*float32(n-1)
+	case AlignJustify:
+		if n > 1 {
+			spacing = (p.Length() - style.Offset - width) / float32(n-1)
+		}
+	}
+	for k, cl := range clusters {
+		adv := cl.advance * scales[k]
+		pt, dir := p.at(d + adv/2)
+		sin, cos := math.Sincos(float64(dir))
+		pt = pt.Add(f32.Pt(float32(sin), float32(-cos)).Mul(style.Shift))
+		// center the character on the point, with its baseline there, and turn it to follow the path
+		a := f32.Affine2D{}.
+			Offset(f32.Pt(-cl.advance/2, 0)).
+			Scale(f32.Point{}, f32.Pt(scales[k], scales[k])).
+			Rotate(f32.Point{}, angles[k]+dir).
+			Offset(pt)
+		c.drawCluster(cl, a, fillcolor)
+		d += adv + spacing
+	}
+}
+
+// drawCluster draws a character, transformed, with the left of its baseline at the origin
+func (c *Canvas) drawCluster(cl pathCluster, a f32.Affine2D, fillcolor color.NRGBA) {
+	stack := op.Affine(a).Push(c.Context.Ops)
+	outline := clip.Outline{Path: c.Theme.Shaper.Shape(cl.glyphs)}.Op().Push(c.Context.Ops)
+	paint.ColorOp{Color: fillcolor}.Add(c.Context.Ops)
+	paint.PaintOp{}.Add(c.Context.Ops)
+	outline.Pop()
+	if call := c.Theme.Shaper.Bitmaps(cl.glyphs); call != (op.CallOp{}) {
+		call.Add(c.Context.Ops)
+	}
+	stack.Pop()
+}
+
+// TextOnPath sets text along a path using percentage-based measures, at the specified size and color
+func (c *Canvas) TextOnPath(p *TextPath, size float32, s string, fillcolor color.NRGBA, style TextPathStyle) {
+	size = pct(size, c.Width)
+	style.Offset = pct(style.Offset, c.Width)
+	style.Spacing = pct(style.Spacing, c.Width)
+	style.Shift = pct(style.Shift, c.Width)
+	c.AbsTextOnPath(p, size, s, fillcolor, style)
+}
@@ -36,24 +36,24 @@ func transforms(w *app.Window) error {
 			stack := canvas.Scale(midx, recty, 2)
 			canvas.CenterRect(midx, recty, rectw, recth, color.NRGBA{0, 0, 128, 128})
 			canvas.TextMid(midx, recty-ts2, ts, "scale", textcolor)
-			gc.EndTransform(stack)
+			canvas.EndTransform(stack)
 
 			recty = 50
 			stack = canvas.Shear(midx, midx, math.Pi/4, 0)
 			canvas.CenterRect(midx, recty, rectw, recth, color.NRGBA{128, 0, 0, 128})
 			canvas.TextMid(midx, recty-ts2, ts, "shear", textcolor)
-			gc.EndTransform(stack)
+			canvas.EndTransform(stack)
 
 			stack = canvas.Translate(20, 85)
 			canvas.CenterRect(midx, recty, rectw, recth, color.NRGBA{0, 128, 0, 128})
 			canvas.TextMid(midx, recty-ts2, ts, "translate", textcolor)
-			gc.EndTransform(stack)
+			canvas.EndTransform(stack)
 
 			recty = 20
 			stack = canvas.Rotate(midx, recty, math.Pi/4)
 			canvas.CenterRect(midx, recty, rectw, recth, color.NRGBA{255, 50, 0, 200})
 			canvas.TextMid(midx, recty-ts2, ts, "rotate", textcolor)
-			gc.EndTransform(stack)
+			canvas.EndTransform(stack)
 			e.Frame(canvas.Context.Ops)
 		}
 	}
@@ -1,34 +1,224 @@
 package giocanvas
 
-import "gioui.org/op"
+import (
+	"math"
+
+	"gioui.org/f32"
+	"gioui.org/op"
+)
 
 // Transformations
 
 // Translate moves current location by (x,y) using percentage-based measures
-func (c *Canvas) Translate(x, y float32) op.TransformStack {
+func (c *Canvas) Translate(x, y float32) TransformStack {
 	x, y = dimen(x, y, c.Width, c.Height)
 	return c.AbsTranslate(x, y)
 }
 
 // Rotate around (x,y) by angle (radians) using percentage-based measures
-func (c *Canvas) Rotate(x, y, angle float32) op.TransformStack {
+func (c *Canvas) Rotate(x, y, angle float32) TransformStack {
 	x, y = dimen(x, y, c.Width, c.Height)
 	return c.AbsRotate(x, y, angle)
 }
 
 // Scale centered at (x,y) by factor using percentage-based measures
-func (c *Canvas) Scale(x, y, factor float32) op.TransformStack {
+func (c *Canvas) Scale(x, y, factor float32) TransformStack {
 	x, y = dimen(x, y, c.Width, c.Height)
 	return c.AbsScale(x, y, factor)
 }
 
 // Shear the object centered at (x,y) using x-angle and y-angle (radians) using percentage-based measures
-func (c *Canvas) Shear(x, y, ax, ay float32) op.TransformStack {
+func (c *Canvas) Shear(x, y, ax, ay float32) TransformStack {
 	x, y = dimen(x, y, c.Width, c.Height)
 	return c.AbsShear(x, y, ax, ay)
 }
 
+// TransformStack is a transformation begun by a canvas. Ending it, by its Pop method,
+// EndTransform or the canvas' Pop, also removes it from the canvas' current transformation.
+type TransformStack struct {
+	op.TransformStack
+	c *Canvas
+}
+
+// Pop ends the transformation
+func (s TransformStack) Pop() {
+	s.c.untrack(s.TransformStack)
+	s.TransformStack.Pop()
+}
+
 // EndTransform ends a transformation
-func EndTransform(stack op.TransformStack) {
+func EndTransform(stack TransformStack) {
 	stack.Pop()
 }
+
+// EndTransform ends a transformation made by the canvas
+func (c *Canvas) EndTransform(stack TransformStack) {
+	stack.Pop()
+}
+
+// Transform is an affine transformation of percentage-based coordinates.
+// The zero value is the identity transformation. Rotation and shear angles
+// have the same sense as the Rotate and Shear methods; on canvases that are not
+// square, they are measured in percent units, so shapes are stretched.
+type Transform struct {
+	m f32.Affine2D
+}
+
+// TransformParts are the components of a transformation, applied in order:
+// scale, shear along x, rotation, and translation
+type TransformParts struct {
+	Translate Point   // offset
+	Rotate    float32 // rotation angle (radians)
+	Shear     float32 // shear angle along x (radians)
+	Scale     Point   // scale factors
+}
+
+// NewTransform makes a transformation from its matrix elements, mapping
+// (x, y) to (a*x + b*y + c, d*x + e*y + f)
+func NewTransform(a, b, c, d, e, f float32) Transform {
+	return Transform{f32.NewAffine2D(a, b, c, d, e, f)}
+}
+
+// Elems returns the matrix elements of t (see NewTransform)
+func (t Transform) Elems() (a, b, c, d, e, f float32) {
+	return t.m.Elems()
+}
+
+// around returns the matrix m applied with origin (x, y), followed by t
+func (t Transform) around(x, y float32, m f32.Affine2D) Transform {
+	m = f32.Affine2D{}.Offset(f32.Pt(x, y)).Mul(m).Mul(f32.Affine2D{}.Offset(f32.Pt(-x, -y)))
+	return Transform{m.Mul(t.m)}
+}
+
+// Translate returns t followed by moving (dx, dy)
+func (t Transform) Translate(dx, dy float32) Transform {
+	return Transform{f32.Affine2D{}.Offset(f32.Pt(dx, dy)).Mul(t.m)}
+}
+
+// Rotate returns t followed by a rotation around (x, y) by angle (radians)
+func (t Transform) Rotate(x, y, angle float32) Transform {
+	s, c := math.Sincos(float64(angle))
+	return t.around(x, y, f32.NewAffine2D(float32(c), float32(s), 0, float32(-s), float32(c), 0))
+}
+
+// Scale returns t followed by scaling by factor, centered at (x, y)
+func (t Transform) Scale(x, y, factor float32) Transform {
+	return t.ScaleXY(x, y, factor, factor)
+}
+
+// ScaleXY returns t followed by scaling by (sx, sy), centered at (x, y)
+func (t Transform) ScaleXY(x, y, sx, sy float32) Transform {
+	return t.around(x, y, f32.NewAffine2D(sx, 0, 0, 0, sy, 0))
+}
+
+// Shear returns t followed by shearing by x-angle and y-angle (radians), centered at (x, y)
+func (t Transform) Shear(x, y, ax, ay float32) Transform {
+	tx, ty := float32(math.Tan(float64(ax))), float32(math.Tan(float64(ay)))
+	return t.around(x, y, f32.NewAffine2D(1, -tx, 0, -ty, 1, 0))
+}
+
+// Mul returns the transformation applying u, then t
+func (t Transform) Mul(u Transform) Transform {
+	return Transform{t.m.Mul(u.m)}
+}
+
+// Invert returns the inverse of t; ok is false if t cannot be inverted
+func (t Transform) Invert() (inverse Transform, ok bool) {
+	a, b, _, d, e, _ := t.m.Elems()
+	if math.Abs(float64(a*e-b*d)) < 1e-12 {
+		return Transform{}, false
+	}
+	return Transform{t.m.Invert()}, true
+}
+
+// Apply returns the point p transformed by t
+func (t Transform) Apply(p Point) Point {
+	q := t.m.Transform(f32.Pt(p.X, p.Y))
+	return Point{q.X, q.Y}
+}
+
+// ApplyPolygon returns the polygon p transformed by t
+func (t Transform) ApplyPolygon(p Polygon) Polygon {
+	q := make(Polygon, len(p))
+	for i, v := range p {
+		q[i] = t.Apply(v)
+	}
+	return q
+}
+
+// Decompose returns the components of t
+func (t Transform) Decompose() TransformParts {
+	a, b, c, d, e, f := t.m.Elems()
+	angle := math.Atan2(float64(-d), float64(a))
+	sin, cos := math.Sincos(angle)
+	sy := sin*float64(b) + cos*float64(e)
+	var shear float64
+	if sy != 0 {
+		shear = math.Atan(-(cos*float64(b) - sin*float64(e)) / sy)
+	}
+	return TransformParts{
+		Translate: Point{c, f},
+		Rotate:    float32(angle),
+		Shear:     float32(shear),
+		Scale:     Point{float32(math.Hypot(float64(a), float64(d))), float32(sy)},
+	}
+}
+
+// Transform makes the transformation from its components
+func (p TransformParts) Transform() Transform {
+	var t Transform
+	return t.ScaleXY(0, 0, p.Scale.X, p.Scale.Y).Shear(0, 0, p.Shear, 0).Rotate(0, 0, p.Rotate).Translate(p.Translate.X, p.Translate.Y)
+}
+
+// pixels returns the matrix converting percentages to pixels on the canvas
+func (c *Canvas) pixels() f32.Affine2D {
+	return f32.NewAffine2D(c.Width/100, 0, 0, 0, -c.Height/100, c.Height)
+}
+
+// PushTransform begins a transformation of subsequent drawing, ended by EndTransform or Pop
+func (c *Canvas) PushTransform(t Transform) TransformStack {
+	p := c.pixels()
+	return c.pushAffine(p.Mul(t.m).Mul(p.Invert()))
+}
+
+// CurrentTransform returns the combination of the transformations made by the canvas
+// that have not been ended, mapping percentage coordinates as drawn to where they appear.
+// Its inverse maps a location on the canvas, such as a pointer, back into the transformed frame.
+func (c *Canvas) CurrentTransform() Transform {
+	var m f32.Affine2D
+	for _, a := range c.applied {
+		m = m.Mul(a.tr)
+	}
+	p := c.pixels()
+	return Transform{p.Invert().Mul(m).Mul(p)}
+}
+
+// applied is a transformation made by the canvas that has not been ended
+type applied struct {
+	stack op.TransformStack
+	tr    f32.Affine2D
+}
+
+// track records a transformation
+func (c *Canvas) track(stack op.TransformStack, tr f32.Affine2D) {
+	c.applied = append(c.applied, applied{stack: stack, tr: tr})
+}
+
+// untrack removes an ended transformation, so that Pop does not end it again
+func (c *Canvas) untrack(stack op.TransformStack) {
+	for n := len(c.saved) - 1; n >= 0; n-- {
+		kept := c.saved[n].transform
+		for i := range kept {
+			if kept[i] == stack {
+				c.saved[n].transform = append(kept[:i], kept[i+1:]...)
+				break
+			}
+		}
+	}
+	for i := len(c.applied) - 1; i >= 0; i-- {
+		if c.applied[i].stack == stack {
+			c.applied = append(c.applied[:i], c.applied[i+1:]...)
+			return
+		}
+	}
+}
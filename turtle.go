@@ -0,0 +1,176 @@
+package giocanvas
+
+import (
+	"image/color"
+	"math"
+	"strings"
+)
+
+// Turtle graphics. The turtle's position is in percentage coordinates, and its heading
+// is in degrees, counterclockwise from the positive x axis (0 points right, 90 points up).
+// Distances are percentages of the canvas width, compensated for the aspect ratio, as with Polar.
+
+// turtleState is the position, heading and pen of a turtle
+type turtleState struct {
+	x, y    float32
+	heading float64
+	down    bool
+	color   color.NRGBA
+	width   float32
+}
+
+// Turtle draws lines on a canvas as it moves
+type Turtle struct {
+	canvas *Canvas
+	turtleState
+	stack []turtleState
+}
+
+// NewTurtle makes a turtle at (x, y), with heading (degrees), and its pen down, drawing black lines of width 0.2
+func (c *Canvas) NewTurtle(x, y float32, heading float64) *Turtle {
+	return &Turtle{canvas: c, turtleState: turtleState{x: x, y: y, heading: heading, down: true, color: color.NRGBA{0, 0, 0, 255}, width: 0.2}}
+}
+
+// Position returns the location of the turtle
+func (t *Turtle) Position() (float32, float32) {
+	return t.x, t.y
+}
+
+// Heading returns the direction of the turtle, in degrees
+func (t *Turtle) Heading() float64 {
+	return t.heading
+}
+
+// Forward moves the turtle along its heading by distance d, drawing a line if the pen is down
+func (t *Turtle) Forward(d float32) {
+	x, y := t.canvas.Polar(t.x, t.y, d, float32(t.heading*math.Pi/180))
+	if t.down {
+		t.canvas.Line(t.x, t.y, x, y, t.width, t.color)
+	}
+	t.x, t.y = x, y
+}
+
+// Back moves the turtle backward by distance d, drawing a line if the pen is down
+func (t *Turtle) Back(d float32) {
+	t.Forward(-d)
+}
+
+// Left turns the turtle counterclockwise by degrees
+func (t *Turtle) Left(degrees float64) {
+	t.heading = math.Mod(t.heading+degrees, 360)
+}
+
+// Right turns the turtle clockwise by degrees
+func (t *Turtle) Right(degrees float64) {
+	t.Left(-degrees)
+}
+
+// Goto moves the turtle to (x, y), drawing a line if the pen is down
+func (t *Turtle) Goto(x, y float32) {
+	if t.down {
+		t.canvas.Line(t.x, t.y, x, y, t.width, t.color)
+	}
+	t.x, t.y = x, y
+}
+
+// SetHeading points the turtle in a direction (degrees)
+func (t *Turtle) SetHeading(degrees float64) {
+	t.heading = degrees
+}
+
+// PenUp lifts the pen, so the turtle moves without drawing
+func (t *Turtle) PenUp() {
+	t.down = false
+}
+
+// PenDown lowers the pen, so the turtle draws as it moves
+func (t *Turtle) PenDown() {
+	t.down = true
+}
+
+// PenColor sets the color of the lines
+func (t *Turtle) PenColor(c color.NRGBA) {
+	t.color = c
+}
+
+// PenWidth sets the width of the lines
+func (t *Turtle) PenWidth(w float32) {
+	t.width = w
+}
+
+// Push saves the position, heading and pen of the turtle
+func (t *Turtle) Push() {
+	t.stack = append(t.stack, t.turtleState)
+}
+
+// Pop restores the position, heading and pen saved by the last Push
+func (t *Turtle) Pop() {
+	if len(t.stack) == 0 {
+		return
+	}
+	t.turtleState = t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+}
+
+// Run follows a program of turtle commands:
+//
+//	F, G  move forward by step, drawing a line
+//	f     move forward by step, without drawing
+//	+, -  turn left, right by angle (degrees)
+//	|     turn around
+//	[, ]  push, pop the turtle state
+//
+// Other characters are ignored.
+func (t *Turtle) Run(program string, step float32, angle float64) {
+	for _, r := range program {
+		switch r {
+		case 'F', 'G':
+			t.Forward(step)
+		case 'f':
+			down := t.down
+			t.down = false
+			t.Forward(step)
+			t.down = down
+		case '+':
+			t.Left(angle)
+		case '-':
+			t.Right(angle)
+		case '|':
+			t.Left(180)
+		case '[':
+			t.Push()
+		case ']':
+			t.Pop()
+		}
+	}
+}
+
+// LSystem is a Lindenmayer system: an axiom, and rules rewriting each
+// character in every generation. Characters without rules are kept.
+type LSystem struct {
+	Axiom string
+	Rules map[rune]string
+	Angle float64 // turning angle of the + and - commands (degrees)
+}
+
+// Expand returns the result of rewriting the axiom n times
+func (l LSystem) Expand(n int) string {
+	s := l.Axiom
+	for i := 0; i < n; i++ {
+		var b strings.Builder
+		for _, r := range s {
+			if rule, ok := l.Rules[r]; ok {
+				b.WriteString(rule)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+		s = b.String()
+	}
+	return s
+}
+
+// Draw expands the system n times, and runs the result with the turtle, moving step for each F
+func (l LSystem) Draw(t *Turtle, n int, step float32) {
+	t.Run(l.Expand(n), step, l.Angle)
+}
@@ -0,0 +1,100 @@
+package giocanvas
+
+import (
+	"image/color"
+	"math"
+	"sort"
+
+	"gioui.org/f32"
+	"gioui.org/op"
+	"gioui.org/text"
+)
+
+// Vertical text. Rotated text is turned a quarter turn, to read from bottom to top, as for the
+// title of a y axis, or from top to bottom; stacked text sets upright characters one below
+// another, as in East Asian layouts. Alignment is along the text, as for horizontal text:
+// the text begins at y, is centered on y, or ends at y. Rotated text has its baseline on x,
+// and stacked text is centered on x.
+
+// TextOrientation is the orientation of vertical text
+type TextOrientation int
+
+const (
+	RotateUp   TextOrientation = iota // turned counterclockwise, reading from bottom to top
+	RotateDown                        // turned clockwise, reading from top to bottom
+	Stacked                           // upright characters, from top to bottom
+)
+
+// vtextops places vertical text, aligned at (x, y)
+func (c *Canvas) vtextops(x, y, size float32, alignment text.Alignment, s string, fillcolor color.NRGBA, orient TextOrientation) {
+	if orient == Stacked {
+		c.stackedText(x, y, size, alignment, s, fillcolor)
+		return
+	}
+	angle := float32(-math.Pi / 2)
+	if orient == RotateDown {
+		angle = math.Pi / 2
+	}
+	// turn the text around its anchor, so that it is aligned along the turned baseline
+	stack := op.Affine(f32.Affine2D{}.Rotate(f32.Pt(x, y), angle)).Push(c.Context.Ops)
+	c.textops(x, y, size, alignment, s, fillcolor)
+	stack.Pop()
+}
+
+// stackedText places the characters of s one below another, centered on x
+func (c *Canvas) stackedText(x, y, size float32, alignment text.Alignment, s string, fillcolor color.NRGBA) {
+	clusters := c.textClusters(s, size)
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i].i < clusters[j].i })
+	_, ascent, descent := c.textWidth("H", size)
+	lead := ascent + descent
+	height := lead * float32(len(clusters))
+	switch alignment {
+	case text.Middle:
+		y -= height / 2
+	case text.End:
+		y -= height
+	}
+	for _, cl := range clusters {
+		c.drawCluster(cl, f32.Affine2D{}.Offset(f32.Pt(x-cl.advance/2, y+ascent)), fillcolor)
+		y += lead
+	}
+}
+
+// AbsVText places vertical text beginning at (x, y)
+func (c *Canvas) AbsVText(x, y, size float32, s string, fillcolor color.NRGBA, orient TextOrientation) {
+	c.vtextops(x, y, size, text.Start, s, fillcolor, orient)
+}
+
+// AbsVTextMid places vertical text centered at (x, y)
+func (c *Canvas) AbsVTextMid(x, y, size float32, s string, fillcolor color.NRGBA, orient TextOrientation) {
+	c.vtextops(x, y, size, text.Middle, s, fillcolor, orient)
+}
+
+// AbsVTextEnd places vertical text ending at (x, y)
+func (c *Canvas) AbsVTextEnd(x, y, size float32, s string, fillcolor color.NRGBA, orient TextOrientation) {
+	c.vtextops(x, y, size, text.End, s, fillcolor, orient)
+}
+
+// VText places vertical text using percentage-based measures,
+// beginning at y, on x, at the specified size, color and orientation
+func (c *Canvas) VText(x, y, size float32, s string, fillcolor color.NRGBA, orient TextOrientation) {
+	x, y = dimen(x, y, c.Width, c.Height)
+	size = pct(size, c.Width)
+	c.vtextops(x, y, size, text.Start, s, fillcolor, orient)
+}
+
+// VTextMid places vertical text using percentage-based measures,
+// centered at y, on x, at the specified size, color and orientation
+func (c *Canvas) VTextMid(x, y, size float32, s string, fillcolor color.NRGBA, orient TextOrientation) {
+	x, y = dimen(x, y, c.Width, c.Height)
+	size = pct(size, c.Width)
+	c.vtextops(x, y, size, text.Middle, s, fillcolor, orient)
+}
+
+// VTextEnd places vertical text using percentage-based measures,
+// ending at y, on x, at the specified size, color and orientation
+func (c *Canvas) VTextEnd(x, y, size float32, s string, fillcolor color.NRGBA, orient TextOrientation) {
+	x, y = dimen(x, y, c.Width, c.Height)
+	size = pct(size, c.Width)
+	c.vtextops(x, y, size, text.End, s, fillcolor, orient)
+}